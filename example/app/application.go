@@ -2,6 +2,7 @@
 package app
 
 import (
+	"context"
 	"log"
 
 	"github.com/go-autowire/autowire/pkg"
@@ -21,13 +22,14 @@ type Application struct {
 	userSvc *service.UserService             `autowire:""`
 }
 
-// Start method is starting application
-func (a Application) Start() {
+// Start method is starting application, it's invoked by pkg.Run function
+func (a Application) Start(_ context.Context) error {
 	log.Println("Config ApiKey : " + a.config.ApiKey()[:3] + "****")
 	userId := "serviceaccount@demo.com" //nolint:revive,stylecheck
 	balance, err := a.userSvc.Balance(userId)
 	if err != nil {
-		log.Fatalln(err.Error())
+		return err
 	}
 	log.Println("Current balance is " + balance.String())
+	return nil
 }
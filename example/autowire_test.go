@@ -1,6 +1,7 @@
 package example_test
 
 import (
+	"context"
 	"log"
 	"math/big"
 	"testing"
@@ -27,8 +28,11 @@ func (TestAuditClient) Send(_ string) {
 }
 
 func TestExampleAutowire(t *testing.T) {
-	defer pkg.Close()
-	application := pkg.Autowired(app.Application{}).(*app.Application)
+	application := pkg.MustGet[*app.Application]()
 	atesting.Spy(application, &TestPaymentServiceTest{}, &TestAuditClient{})
-	application.Start()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pkg.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
 }
@@ -7,12 +7,12 @@ import (
 
 //nolint:gochecknoinits
 func init() {
-	pkg.Autowire(New("default"))
+	pkg.Autowire(&ApplicationConfig{})
 }
 
 // A ApplicationConfig represents name struct, which hold application configuration
 type ApplicationConfig struct {
-	apiKey string
+	apiKey string `value:"${API_KEY:default}"`
 }
 
 // New returns new ApplicationConfig
@@ -7,9 +7,7 @@ import (
 
 func init() { //nolint:gochecknoinits
 	pkg.Autowire(&UserService{})
-	pkg.RunProd(func() {
-		pkg.Autowire(&BankAccountService{})
-		pkg.Autowire(&PaypalService{})
-	})
+	pkg.Register(&BankAccountService{}, pkg.OnProduction())
+	pkg.Register(&PaypalService{}, pkg.OnProduction())
 	pkg.Autowire(&AuditService{})
 }
@@ -11,8 +11,8 @@ import (
 // A UserService represents a named struct
 type UserService struct {
 	PaymentSvc         PaymentService                `autowire:"service/BankAccountService"`
-	auditClient        EventSender                   `autowire:"service/AuditService"`
-	userRoleRepository repository.UserRoleRepository `autowire:"repository/InMemoryUserRoleRepository"`
+	auditClient        EventSender                   `autowire:""`
+	userRoleRepository repository.UserRoleRepository `autowire:""`
 }
 
 // Balance is a method returning current balance of the user.
@@ -1,10 +1,12 @@
-// Package atesting provides Spy function for easy way to mock dependencies
+// Package atesting provides Spy function for easy way to mock dependencies and
+// AssertFullyWired function verifying the dependency graph
 package atesting
 
 import (
 	"container/list"
 	"log"
 	"reflect"
+	"testing"
 
 	"github.com/go-autowire/autowire/pkg"
 	"github.com/go-autowire/autowire/pkg/internal"
@@ -25,8 +27,37 @@ import (
 //   - `v`          : pointer to structure inside which spy object will be injected
 //   - `dependencies` : this is variadic argument, pointer to mocked structures which are gonna be injected
 func Spy(v interface{}, dependencies ...interface{}) {
+	spy(defaultContainer{}, v, dependencies)
+}
+
+// SpyContainer function works like Spy function, but registers the spies inside
+// the given Container and looks up the dependencies to spy on there.
+//   SpyContainer(c, application, &TestAuditClient{})
+func SpyContainer(c *pkg.Container, v interface{}, dependencies ...interface{}) {
+	spy(c, v, dependencies)
+}
+
+// container represents either the default Container or the one passed to SpyContainer function.
+type container interface {
+	Autowire(values ...interface{})
+	Validate() error
+}
+
+// defaultContainer delegates to the package level functions operating on the default Container.
+type defaultContainer struct{}
+
+func (defaultContainer) Autowire(values ...interface{}) {
+	pkg.Autowire(values...)
+}
+
+func (defaultContainer) Validate() error {
+	return pkg.Validate()
+}
+
+func spy(c container, v interface{}, dependencies []interface{}) {
 	queue := list.New()
 	queue.PushBack(v)
+	visited := map[interface{}]bool{v: true}
 	for queue.Len() > 0 {
 		elemQueue := queue.Front()
 		value := reflect.ValueOf(elemQueue.Value)
@@ -38,33 +69,73 @@ func Spy(v interface{}, dependencies ...interface{}) {
 		for i := 0; i < elem.NumField(); i++ {
 			field := elem.Type().Field(i)
 			tag, ok := field.Tag.Lookup(pkg.Tag)
-			if ok {
+			if ok && isSpyable(field.Type) {
 				if tag != "" {
 					for _, currentDependency := range dependencies {
 						dependValue := reflect.ValueOf(currentDependency)
-						if dependValue.Type().Implements(field.Type) {
+						if field.Type.Kind() == reflect.Interface && dependValue.Type().Implements(field.Type) {
 							t := reflect.New(dependValue.Type())
 							log.Println("Injecting Spy on currentDependency by tag " + tag + " will be used " + t.Type().String())
-							pkg.Autowire(currentDependency)
+							c.Autowire(currentDependency)
 							internal.SetFieldValue(elem, i, currentDependency)
 						}
 					}
 				} else {
 					for _, currentDependency := range dependencies {
-						log.Printf("Checking compatibility between %s & %s", reflect.TypeOf(currentDependency), elem.Field(i).Type())
-						if reflect.TypeOf(currentDependency) == elem.Field(i).Type() {
+						fieldType := elem.Field(i).Type()
+						dependType := reflect.TypeOf(currentDependency)
+						log.Printf("Checking compatibility between %s & %s", dependType, fieldType)
+						if dependType == fieldType || (fieldType.Kind() == reflect.Interface && dependType.Implements(fieldType)) {
 							internal.SetFieldValue(elem, i, currentDependency)
 						}
 					}
 				}
 				if !elem.Field(i).IsNil() {
-					if elem.Field(i).Elem().CanInterface() {
-						queue.PushBack(pkg.Autowired(elem.Field(i).Elem().Interface()))
-					} else {
-						queue.PushBack(pkg.Autowired(internal.GetUnexportedField(elem.Field(i))))
+					next := internal.GetUnexportedField(elem.Field(i))
+					nextValue := reflect.ValueOf(next)
+					if nextValue.Kind() == reflect.Ptr && nextValue.Elem().Kind() == reflect.Struct && !visited[next] {
+						visited[next] = true
+						queue.PushBack(next)
 					}
 				}
 			}
 		}
 	}
 }
+
+// isSpyable function reports whether the field could hold a spy, which is true
+// for struct pointers and interfaces, while collections, Lazy, Provider and
+// config fields are skipped.
+func isSpyable(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr
+}
+
+// AssertFullyWired function fails the test, when the dependency graph contains
+// struct fields still waiting for their dependencies or constructors, whose
+// arguments couldn't be resolved. Failure message lists all of them.
+// For more information take a look at pkg.Validate function.
+// Example:
+//   func TestApplicationWiring(t *testing.T) {
+//       atesting.AssertFullyWired(t)
+//   }
+func AssertFullyWired(t testing.TB) bool {
+	t.Helper()
+	return assertFullyWired(t, defaultContainer{})
+}
+
+// AssertContainerFullyWired function works like AssertFullyWired function, but
+// verifies the dependency graph of the given Container.
+//   atesting.AssertContainerFullyWired(t, c)
+func AssertContainerFullyWired(t testing.TB, c *pkg.Container) bool {
+	t.Helper()
+	return assertFullyWired(t, c)
+}
+
+func assertFullyWired(t testing.TB, c container) bool {
+	t.Helper()
+	if err := c.Validate(); err != nil {
+		t.Error(err.Error())
+		return false
+	}
+	return true
+}
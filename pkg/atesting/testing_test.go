@@ -1,6 +1,7 @@
 package atesting_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/go-autowire/autowire/pkg"
@@ -99,3 +100,171 @@ func TestSpyInterface(t *testing.T) {
 	assert.Equal(t, tmpBaz.MyBaz.(*Bar).Name, testBarName)
 	assert.Equal(t, 0, len(pkg.Close()))
 }
+
+// Qux represent named struct autowiring interfaces by type
+type Qux struct {
+	MyFoo FooEr `autowire:""`
+	myBar BarEr `autowire:""`
+}
+
+func TestSpyInterfaceByType(t *testing.T) {
+	pkg.Autowire(&Foo{Name: fooName})
+	pkg.Autowire(&Bar{Name: barName})
+	tmpQux := &Qux{}
+	pkg.Autowire(tmpQux)
+	assert.Equal(t, tmpQux.MyFoo.(*Foo).Name, fooName)
+	assert.Equal(t, tmpQux.myBar.(*Bar).Name, barName)
+	atesting.Spy(tmpQux, &Foo{Name: testFooName}, &Bar{Name: testBarName})
+	assert.Equal(t, tmpQux.MyFoo.(*Foo).Name, testFooName)
+	assert.Equal(t, tmpQux.myBar.(*Bar).Name, testBarName)
+	assert.Equal(t, 0, len(pkg.Close()))
+}
+
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestAssertFullyWired(t *testing.T) {
+	pkg.Autowire(&Foo{Name: fooName})
+	assert.True(t, atesting.AssertFullyWired(t))
+	pkg.Autowire(&FooBar{})
+	recorder := &recordingT{}
+	assert.False(t, atesting.AssertFullyWired(recorder))
+	assert.Equal(t, 1, len(recorder.errors))
+	assert.Contains(t, recorder.errors[0], "field Bar")
+	pkg.Autowire(&Bar{Name: barName})
+	assert.True(t, atesting.AssertFullyWired(t))
+	assert.Equal(t, 0, len(pkg.Close()))
+}
+
+// Plugins represent struct autowiring all the implementations of the interface
+type Plugins struct {
+	Foo  *Foo             `autowire:""`
+	All  []FooEr          `autowire:""`
+	Keys map[string]FooEr `autowire:""`
+}
+
+func TestSpySkipsCollections(t *testing.T) {
+	pkg.Autowire(&Foo{Name: fooName})
+	plugins := &Plugins{}
+	pkg.Autowire(plugins)
+	assert.Equal(t, 1, len(plugins.All))
+	atesting.Spy(plugins, &Foo{Name: testFooName})
+	assert.Equal(t, testFooName, plugins.Foo.Name)
+	assert.Equal(t, fooName, plugins.All[0].(*Foo).Name)
+	assert.Equal(t, 0, len(pkg.Close()))
+}
+
+func TestSpyContainer(t *testing.T) {
+	c := pkg.NewContainer()
+	c.Autowire(&Foo{Name: fooName}, &Bar{Name: barName})
+	tmpBaz := &Baz{}
+	c.Autowire(tmpBaz)
+	atesting.SpyContainer(c, tmpBaz, &Foo{Name: testFooName})
+	assert.Equal(t, testFooName, tmpBaz.MyFoo.(*Foo).Name)
+	assert.Equal(t, barName, tmpBaz.MyBaz.(*Bar).Name)
+	_, ok := pkg.Get[*Foo]()
+	assert.False(t, ok)
+}
+
+func TestAssertContainerFullyWired(t *testing.T) {
+	c := pkg.NewContainer()
+	c.Autowire(&FooBar{})
+	recorder := &recordingT{}
+	assert.False(t, atesting.AssertContainerFullyWired(recorder, c))
+	assert.Equal(t, 1, len(recorder.errors))
+	assert.True(t, atesting.AssertFullyWired(t))
+	c.Autowire(&Foo{Name: fooName}, &Bar{Name: barName})
+	assert.True(t, atesting.AssertContainerFullyWired(t, c))
+}
+
+// Deferred represent struct holding Lazy and Provider fields
+type Deferred struct {
+	Foo      *Foo               `autowire:""`
+	LazyFoo  pkg.Lazy[*Foo]     `autowire:""`
+	LazyBar  pkg.Lazy[BarEr]    `autowire:"Bar"`
+	Provider pkg.Provider[*Foo] `autowire:""`
+}
+
+func TestSpySkipsLazyFields(t *testing.T) {
+	pkg.Autowire(&Foo{Name: fooName}, &Bar{Name: barName})
+	deferred := &Deferred{}
+	pkg.Autowire(deferred)
+	atesting.Spy(deferred, &Foo{Name: testFooName}, &Bar{Name: testBarName})
+	assert.Equal(t, testFooName, deferred.Foo.Name)
+	assert.Equal(t, fooName, deferred.LazyFoo.Get().Name)
+	assert.Equal(t, barName, deferred.LazyBar.Get().(*Bar).Name)
+	assert.Equal(t, fooName, deferred.Provider.Get().Name)
+	assert.Equal(t, 0, len(pkg.Close()))
+}
+
+// Server represent struct bound to the configuration
+type Server struct {
+	_    struct{} `autowire:",config=server"`
+	Port int
+	Foo  *Foo `autowire:""`
+}
+
+func TestSpySkipsConfigFields(t *testing.T) {
+	c := pkg.NewContainer()
+	assert.Nil(t, c.SetProperty("server.port", "8080"))
+	server := &Server{}
+	c.Autowire(&Foo{Name: fooName}, server)
+	assert.Equal(t, 8080, server.Port)
+	atesting.SpyContainer(c, server, &Foo{Name: testFooName})
+	assert.Equal(t, testFooName, server.Foo.Name)
+	assert.Equal(t, 8080, server.Port)
+}
+
+// Node represent struct registered both unnamed and named
+type Node struct {
+	P FooEr `autowire:""`
+}
+
+// Replicated represent struct depending on the named struct
+type Replicated struct {
+	Replica *Node `autowire:"name=replica"`
+}
+
+func TestSpyNamedField(t *testing.T) {
+	c := pkg.NewContainer()
+	primary := &Node{}
+	replica := &Node{}
+	c.Autowire(&Foo{Name: fooName}, primary)
+	c.AutowireNamed("replica", replica)
+	replicated := &Replicated{}
+	c.Autowire(replicated)
+	assert.Same(t, replica, replicated.Replica)
+	atesting.SpyContainer(c, replicated, &Foo{Name: testFooName})
+	assert.Equal(t, testFooName, replicated.Replica.P.(*Foo).Name)
+	assert.Equal(t, fooName, primary.P.(*Foo).Name)
+}
+
+// Report represent struct provided with Prototype lifetime
+type Report struct {
+	Foo *Foo `autowire:""`
+}
+
+// Reporter represent struct holding both fresh and prototype instances of Report
+type Reporter struct {
+	Fresh     *Report `autowire:",new"`
+	Prototype *Report `autowire:""`
+}
+
+func TestSpyFreshAndPrototypeFields(t *testing.T) {
+	c := pkg.NewContainer()
+	c.Provide(func() *Report { return &Report{} }, pkg.Prototype)
+	reporter := &Reporter{}
+	c.Autowire(&Foo{Name: fooName}, reporter)
+	assert.NotSame(t, reporter.Fresh, reporter.Prototype)
+	atesting.SpyContainer(c, reporter, &Foo{Name: testFooName})
+	assert.Equal(t, testFooName, reporter.Fresh.Foo.Name)
+	assert.Equal(t, testFooName, reporter.Prototype.Foo.Name)
+}
@@ -1,22 +1,18 @@
 package pkg
 
 import (
-	"io"
 	"log"
 	"reflect"
 	"regexp"
-	"strings"
 
 	"github.com/go-autowire/autowire/pkg/internal"
 )
 
 var (
 	//nolint:gochecknoglobals
-	dependencies map[string]interface{}
+	defaultContainer = NewContainer()
 	//nolint:gochecknoglobals
-	requiredDependencies map[string]map[string]interface{}
-	//nolint:gochecknoglobals
-	currentProfile = internal.GetProfile()
+	currentProfile, profileSource = internal.DetectProfile()
 )
 
 // Tag respresents autowire go tag.
@@ -26,15 +22,45 @@ const Tag = "autowire"
 func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("Init Autowire Context")
-	dependencies = make(map[string]interface{})
-	requiredDependencies = make(map[string]map[string]interface{})
+	log.Printf("Profile %s detected from %s", currentProfile, profileSource)
+}
+
+// Profile represents the profile of the program, either Production or Testing. Unlike
+// the named profiles set by SetProfiles function, it tells whether the program is
+// running inside go tests. The profile is taken from AUTOWIRE_PROFILE environment
+// variable holding either production or testing, afterwards from testing.Testing()
+// on Go 1.21 and later. Detection from the program name is enabled only by
+// AUTOWIRE_PROFILE_PROGRAM_NAME environment variable, otherwise Production is used.
+type Profile = internal.Profile
+
+const (
+	// Production profile
+	Production = internal.Production
+	// Testing profile
+	Testing = internal.Testing
+)
+
+// SetProfile function overrides the detected profile of the program, e.g. test
+// binaries built by other tools could call it inside TestMain function:
+//  pkg.SetProfile(pkg.Testing)
+// SetProfile should be invoked before the structs are registered.
+func SetProfile(profile Profile) {
+	currentProfile, profileSource = profile, "SetProfile"
+	log.Printf("Profile %s set explicitly", profile)
+}
+
+// CurrentProfile function returns the profile of the program.
+func CurrentProfile() Profile {
+	return currentProfile
 }
 
 // RunProd executes function in case environment is production only, this way
 // it is preventing execution of it inside go tests.
 // This flexibility could help if you want to skip autowiring struct in our tests.
+//
+// Deprecated: use Register or Provide function with OnProduction condition instead.
 func RunProd(runFunc func()) {
-	if currentProfile == internal.Production {
+	if currentProfile == Production {
 		runFunc()
 	}
 }
@@ -70,8 +96,64 @@ func RunProd(runFunc func()) {
 //      userRoleRepository UserRoleRepository `autowire:"repository/InMemoryUserRoleRepository"`
 //  }
 // UserRoleRepository is simply an interface and InMemoryUserRoleRepository is a
-// struct, which implements that interface. For more information take a look at
+// struct, which implements that interface. The tag should hold either the full
+// path of the struct or its suffix starting right after a path separator, when more
+// than one dependency matches the tag wiring fails with ErrAmbiguous error listing
+// all the candidates. For more information take a look at
 // example package: https://github.com/go-autowire/autowire/tree/main/example.
+// When the tag of interface field is empty, the single registered struct implementing
+// the interface is injected. Wiring fails with ErrAmbiguous error in case several
+// implementations are registered:
+//  type UserService struct {
+//      userRoleRepository UserRoleRepository `autowire:""`
+//  }
+//
+// Multi Injection
+//
+// Slice of interfaces and map of interfaces keyed by string marked with empty tag
+// receive all the registered implementations of the interface:
+//  type PaymentGateway struct {
+//      Providers []PaymentService          `autowire:""`
+//      ByName    map[string]PaymentService `autowire:""`
+//  }
+// Map is keyed by the name of the implementation or its full path, while slice
+// is sorted by the Order method of Ordered interface and registration order.
+// The collections are populated once again when new implementation is registered.
+//
+// Optional Injection
+//
+// Fields are required by default, struct fields still waiting for their dependencies
+// are reported by Validate method of the Container. Fields marked with optional
+// option are left nil without any warnings, unless the dependency is registered:
+//  type UserService struct {
+//      auditClient EventSender `autowire:"service/AuditService,optional"`
+//  }
+//
+// Fresh Injection
+//
+// Fields marked with new option receive freshly constructed and wired instance,
+// which isn't shared with any other struct, e.g. stateful buffers or builders.
+// The instance is constructed by the provider registered via Provide function,
+// otherwise zero value of the struct is used:
+//  type ReportService struct {
+//      builder *ReportBuilder `autowire:",new"`
+//  }
+// Dependencies provided with Prototype lifetime are always injected as fresh instances.
+//
+// Value Injection
+//
+// Fields annotated with "value" tag are filled with configuration values taken from
+// environment variables, configuration files loaded by LoadConfig function and properties
+// set by SetProperty function. ${KEY:default} placeholders are replaced with the value of the
+// KEY or the default, while the result is converted into string, bool, integer, float,
+// time.Duration or comma separated slice of them:
+//  type ApplicationConfig struct {
+//      apiKey  string        `value:"${API_KEY:default}"`
+//      timeout time.Duration `value:"${TIMEOUT:5s}"`
+//      hosts   []string      `value:"${HOSTS:localhost}"`
+//  }
+// Value, which couldn't be converted, is reported as *WiringError caused by ErrInvalidValue,
+// while placeholder without default referring to missing KEY is reported by Validate function.
 // Very Simplified Example:
 //		type App struct {}
 //		func init()  {
@@ -80,40 +162,41 @@ func RunProd(runFunc func()) {
 // As mentioned above Autowire function cloud be invoked in the package init function,
 // but also it is possible to do it in the main function of the application,
 // or separate files, which will be responsible for autowiring all the structs.
+// Autowire function registers the structs inside the default Container, in order
+// to build an isolated dependency graph take a look at NewContainer.
 func Autowire(values ...interface{}) {
-	for _, v := range values {
-		autowire(v)
-		depPath := getStructPtrFullPath(reflect.ValueOf(v))
-		if uncompletedDepMap, found := requiredDependencies[depPath]; found {
-			for uncompleted := range uncompletedDepMap {
-				if dep, ok := dependencies[uncompleted]; ok { // check for tags
-					delete(uncompletedDepMap, uncompleted)
-					autowireDependencies(reflect.ValueOf(dep))
-				}
-			}
-		}
-	}
+	defaultContainer.Autowire(values...)
 }
 
-func autowire(v interface{}) {
-	value := reflect.ValueOf(v)
-	switch value.Kind() { //nolint:exhaustive
-	case reflect.Ptr:
-		structType := getStructPtrFullPath(value)
-		_, ok := dependencies[structType]
-		if ok {
-			log.Printf("%s already autowired... ignored", structType)
-		} else {
-			log.Printf("Autowiring %s", structType)
-			autowireDependencies(value)
-			dependencies[structType] = v
-		}
-	case reflect.Invalid:
-		log.Panicln("invalid reflection type")
-	default: // reflect.Array, reflect.Struct, reflect.Interface, etc.
-		log.Panicf("autowiring structs is unsupported, expected to receive struct pointer(*%s)",
-			value.Type().String())
-	}
+// TryAutowire function works like Autowire function, but instead of panicking
+// returns *WiringError, which could be inspected with errors.Is and errors.As functions:
+//  if err := TryAutowire(&App{}); errors.Is(err, ErrInterfaceMismatch) {
+//      return err
+//  }
+// This way wiring failures could be reported by the startup error handling of the application.
+func TryAutowire(values ...interface{}) error {
+	return defaultContainer.TryAutowire(values...)
+}
+
+// AutowireNamed function works like Autowire function, but registers the struct
+// under the given name. This way more than one instance of the same type could
+// be registered, e.g. primary and replica database:
+//  AutowireNamed("primaryDB", &DB{})
+//  AutowireNamed("replicaDB", &DB{})
+// Named instance is injected into the struct field marked with name option of autowire tag:
+//  type UserRepository struct {
+//      db *DB `autowire:"name=replicaDB"`
+//  }
+// Named instances are neither injected by the full path nor returned by Autowired
+// function called with struct, instead Autowired function should be called with the name.
+func AutowireNamed(name string, v interface{}) {
+	defaultContainer.AutowireNamed(name, v)
+}
+
+// TryAutowireNamed function works like AutowireNamed function, but instead of panicking
+// returns *WiringError describing the failure.
+func TryAutowireNamed(name string, v interface{}) error {
+	return defaultContainer.TryAutowireNamed(name, v)
 }
 
 // Autowired function returns fully initialized instance with all dependencies, which is ready to be used.
@@ -121,46 +204,31 @@ func autowire(v interface{}) {
 // Take a look at https://golang.org/ref/spec#Type_assertions for more information.
 // The following snippet demonstrate simple usage of it :
 // 	 app := Autowired(app.Application{}).(*app.Application)
+// Instances registered with AutowireNamed function are returned when Autowired is called with the name:
+// 	 db := Autowired("replicaDB").(*DB)
+// In order to avoid type assertions take a look at Get and MustGet functions.
 func Autowired(v interface{}) interface{} {
-	value := reflect.ValueOf(v)
-	var path string
-	switch value.Kind() { //nolint:exhaustive
-	case reflect.Struct:
-		path = getFullPath(value.Type().PkgPath(), value.Type().String())
-	case reflect.Ptr:
-		path = getStructPtrFullPath(value)
-	default:
-		log.Panicln("Unknown Autowired Typed!")
-	}
-	dependency, ok := dependencies[path]
-	if ok {
-		return dependency
-	}
-	return nil
+	return defaultContainer.Autowired(v)
+}
+
+// TryAutowired function works like Autowired function, but instead of panicking
+// on unsupported types or returning nil for unknown dependencies it returns
+// *WiringError caused by ErrNotPointer or ErrNotFound.
+func TryAutowired(v interface{}) (interface{}, error) {
+	return defaultContainer.TryAutowired(v)
 }
 
 // Close function invoke Close method on each autowired struct
 // which implements io.Closer interface, so currently active
 // occupied resources (connections, channels, descriptor, etc.)
-// could be released. Returning slice of occurred errors.
+// could be released. Structs are closed in reverse dependency order,
+// so each struct is closed before all of its dependencies, e.g. service
+// flushing through the repository is closed before the repository.
+// Returning slice of occurred errors, each of them is *WiringError
+// caused by ErrCloseFailed holding the path of the failed struct.
 // Close functions cleans the dependency graph.
 func Close() []error {
-	log.Println("Closing...")
-	var errors []error
-	for key, dependency := range dependencies {
-		valueDepend := reflect.ValueOf(dependency)
-		closerType := reflect.TypeOf((*io.Closer)(nil)).Elem()
-		if valueDepend.Type().Implements(closerType) {
-			err := dependency.(io.Closer).Close()
-			if err != nil {
-				log.Println(err.Error())
-				errors = append(errors, err)
-			}
-		}
-		delete(dependencies, key)
-	}
-	requiredDependencies = make(map[string]map[string]interface{})
-	return errors
+	return defaultContainer.Close()
 }
 
 func getStructPtrFullPath(value reflect.Value) string {
@@ -171,64 +239,3 @@ func getFullPath(pkgPath string, typePath string) string {
 	var re = regexp.MustCompile(`^\*?.+\.`)
 	return pkgPath + re.ReplaceAllString(typePath, "/")
 }
-
-func autowireDependencies(value reflect.Value) {
-	structType := getStructPtrFullPath(value)
-	elem := value.Elem()
-	for i := 0; i < elem.NumField(); i++ {
-		field := elem.Type().Field(i)
-		tag, ok := field.Tag.Lookup(Tag)
-		if ok {
-			var t reflect.Value
-			if tag != "" {
-				currentDep := findDependency(tag)
-				if len(currentDep) == 0 {
-					msg := "Unknown dependency " + tag + " found none"
-					if currentProfile != internal.Testing {
-						log.Println(msg)
-					} else {
-						log.Println(msg + ", ready for spy")
-					}
-					markStructUninitialized(structType, tag)
-				} else {
-					v := reflect.ValueOf(currentDep[0])
-					if v.Type().Implements(field.Type) {
-						t = reflect.New(v.Type())
-						dependency := currentDep[0]
-						internal.SetFieldValue(elem, i, dependency)
-					} else {
-						log.Panicln(v.Type().String() + " doesnt Implements: " + field.Type.String())
-					}
-				}
-			} else {
-				t = reflect.New(elem.Type().Field(i).Type.Elem())
-				dependency, found := dependencies[getStructPtrFullPath(t)]
-				if found {
-					internal.SetFieldValue(elem, i, dependency)
-				} else {
-					markStructUninitialized(structType, getStructPtrFullPath(t))
-				}
-			}
-		}
-	}
-}
-
-func markStructUninitialized(structType string, depName string) {
-	if depMap, ok := requiredDependencies[depName]; ok {
-		depMap[structType] = true
-	} else {
-		requiredDependencies[depName] = map[string]interface{}{}
-		depMap = requiredDependencies[depName]
-		depMap[structType] = true
-	}
-}
-
-func findDependency(tagDependencyType string) []interface{} {
-	var result []interface{}
-	for tmp, dep := range dependencies {
-		if strings.Contains(tmp, tagDependencyType) {
-			result = append(result, dep)
-		}
-	}
-	return result
-}
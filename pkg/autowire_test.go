@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -17,14 +18,20 @@ const (
 )
 
 func TestRunProd(t *testing.T) {
-	currentProfile = internal.Production
+	SetProfile(Production)
 	callCount := 0
 	RunProd(func() {
 		callCount++
 	})
 	assert.Equal(t, callCount, 1)
+	assert.Equal(t, "SetProfile", profileSource)
 	// set it back to test mode
-	currentProfile = internal.Testing
+	SetProfile(Testing)
+}
+
+func TestCurrentProfile(t *testing.T) {
+	assert.Equal(t, Testing, CurrentProfile())
+	assert.Equal(t, "testing", CurrentProfile().String())
 }
 
 func TestRunProdSkippedInTests(t *testing.T) {
@@ -41,12 +48,12 @@ func Test_Close(t *testing.T) {
 	assert.Equal(t, value.Kind(), reflect.Ptr)
 	structType := getStructPtrFullPath(value)
 	assert.Equal(t, structType, packageName+"/internal/fake/Foo")
-	dependencies[structType] = &tmp
+	defaultContainer.dependencies[structType] = &tmp
 	assert.Equal(t, tmp.CloseCalls, 0)
 	errors := Close()
 	assert.Equal(t, len(errors), 0)
 	assert.Equal(t, tmp.CloseCalls, 1)
-	delete(dependencies, structType)
+	delete(defaultContainer.dependencies, structType)
 }
 
 type closeError struct {
@@ -66,12 +73,12 @@ func Test_CloseError(t *testing.T) {
 	assert.Equal(t, value.Kind(), reflect.Ptr)
 	structType := getStructPtrFullPath(value)
 	assert.Equal(t, structType, packageName+"/closeError")
-	dependencies[structType] = &tmp
+	defaultContainer.dependencies[structType] = &tmp
 	assert.Equal(t, tmp.closeCalls, 0)
 	errors := Close()
 	assert.Equal(t, len(errors), 1)
 	assert.Equal(t, tmp.closeCalls, 1)
-	delete(dependencies, structType)
+	delete(defaultContainer.dependencies, structType)
 }
 
 func Test_getStructPtrFullPath(t *testing.T) {
@@ -87,13 +94,13 @@ func Test_findDependency(t *testing.T) {
 	tmp := fake.Foo{Name: "test"}
 	value := reflect.ValueOf(&tmp)
 	structType := getStructPtrFullPath(value)
-	dependencies[structType] = &tmp
-	deps := findDependency(tag)
+	defaultContainer.dependencies[structType] = &tmp
+	deps := defaultContainer.findDependency(tag)
 	assert.Equal(t, len(deps), 1)
-	dependency := deps[0]
+	dependency := defaultContainer.dependencies[deps[0]]
 	dependencyType := reflect.TypeOf(dependency)
 	assert.Equal(t, dependencyType.String(), reflect.TypeOf(&fake.Foo{}).String())
-	delete(dependencies, structType)
+	delete(defaultContainer.dependencies, structType)
 }
 
 func TestAutowireUnexportedStruct(t *testing.T) {
@@ -102,7 +109,7 @@ func TestAutowireUnexportedStruct(t *testing.T) {
 	assert.Nil(t, getFieldByName(tmpBar, myFooFieldName))
 	Autowire(tmpBar)
 	assert.NotNil(t, getFieldByName(tmpBar, myFooFieldName))
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireExportedStruct(t *testing.T) {
@@ -111,7 +118,7 @@ func TestAutowireExportedStruct(t *testing.T) {
 	assert.Nil(t, tmpBaz.MyFoo)
 	Autowire(tmpBaz)
 	assert.NotNil(t, tmpBaz.MyFoo)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireExportedInterface(t *testing.T) {
@@ -120,7 +127,7 @@ func TestAutowireExportedInterface(t *testing.T) {
 	assert.Nil(t, tmpBaz.Passer)
 	Autowire(tmpBaz)
 	assert.NotNil(t, tmpBaz.Passer)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireUnexportedInterface(t *testing.T) {
@@ -129,7 +136,7 @@ func TestAutowireUnexportedInterface(t *testing.T) {
 	assert.Nil(t, tmpBaz.Passer())
 	Autowire(tmpBaz)
 	assert.NotNil(t, tmpBaz.Passer)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireStructNotImplementingInterface(t *testing.T) {
@@ -140,7 +147,7 @@ func TestAutowireStructNotImplementingInterface(t *testing.T) {
 		Autowire(tmp)
 	}
 	assert.Panics(t, panicFunc)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireUnknownStructOnInterfacePlaceholder(t *testing.T) {
@@ -149,8 +156,8 @@ func TestAutowireUnknownStructOnInterfacePlaceholder(t *testing.T) {
 	assert.Nil(t, getFieldByName(tmpDep, passerFieldName))
 	Autowire(tmpDep)
 	assert.Nil(t, getFieldByName(tmpDep, passerFieldName))
-	dependencies = make(map[string]interface{})
-	requiredDependencies = make(map[string]map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
+	defaultContainer.requiredDependencies = make(map[string]map[string]unresolvedFields)
 }
 
 func TestAutowireDependencyAlreadyAutowired(t *testing.T) {
@@ -159,8 +166,8 @@ func TestAutowireDependencyAlreadyAutowired(t *testing.T) {
 	secondFoo := &fake.Foo{}
 	Autowire(secondFoo)
 	structType := getStructPtrFullPath(reflect.ValueOf(secondFoo))
-	assert.Equal(t, dependencies[structType], fistFoo)
-	dependencies = make(map[string]interface{})
+	assert.Equal(t, defaultContainer.dependencies[structType], fistFoo)
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireUnorderedUnexportedStructDependencies(t *testing.T) {
@@ -168,8 +175,8 @@ func TestAutowireUnorderedUnexportedStructDependencies(t *testing.T) {
 	assert.Nil(t, getFieldByName(tmpBar, myFooFieldName))
 	Autowire(tmpBar, &fake.Foo{})
 	assert.NotNil(t, getFieldByName(tmpBar, myFooFieldName))
-	dependencies = make(map[string]interface{})
-	requiredDependencies = make(map[string]map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
+	defaultContainer.requiredDependencies = make(map[string]map[string]unresolvedFields)
 }
 
 func TestAutowireUnorderedExportedStructDependencies(t *testing.T) {
@@ -177,8 +184,8 @@ func TestAutowireUnorderedExportedStructDependencies(t *testing.T) {
 	assert.Nil(t, tmpBaz.MyFoo)
 	Autowire(tmpBaz, &fake.Foo{})
 	assert.NotNil(t, tmpBaz.MyFoo)
-	dependencies = make(map[string]interface{})
-	requiredDependencies = make(map[string]map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
+	defaultContainer.requiredDependencies = make(map[string]map[string]unresolvedFields)
 }
 
 func TestAutowireUnorderedUnexportedInterfaceDependencies(t *testing.T) {
@@ -186,7 +193,7 @@ func TestAutowireUnorderedUnexportedInterfaceDependencies(t *testing.T) {
 	assert.Nil(t, tmpBaz.Passer())
 	Autowire(tmpBaz, &fake.Foo{})
 	assert.NotNil(t, tmpBaz.Passer)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireUnorderedExportedInterfaceDependencies(t *testing.T) {
@@ -195,7 +202,7 @@ func TestAutowireUnorderedExportedInterfaceDependencies(t *testing.T) {
 	assert.Nil(t, tmpBaz.Passer)
 	Autowire(tmpBaz)
 	assert.NotNil(t, tmpBaz.Passer)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowireInvalid(t *testing.T) {
@@ -220,7 +227,7 @@ func TestAutowired(t *testing.T) {
 	assert.Equal(t, tmpBar, resultStruct)
 	resultPtrStruct := Autowired(&fake.Bar{}).(*fake.Bar)
 	assert.Equal(t, tmpBar, resultPtrStruct)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func TestAutowiredNotFound(t *testing.T) {
@@ -233,9 +240,50 @@ func TestAutowiredInvalid(t *testing.T) {
 		Autowired(nil)
 	}
 	assert.Panics(t, panicFunc)
-	dependencies = make(map[string]interface{})
+	defaultContainer.dependencies = make(map[string]interface{})
 }
 
 func getFieldByName(v interface{}, fieldName string) interface{} {
 	return internal.GetUnexportedField(reflect.ValueOf(v).Elem().FieldByName(fieldName))
 }
+
+func TestAutowireInterfaceByType(t *testing.T) {
+	c := NewContainer()
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	tmpQuy := &fake.Quy{}
+	c.Autowire(tmpQuy)
+	assert.Equal(t, foo, tmpQuy.Passer)
+	tmpQuv := &fake.Quv{}
+	c.Autowire(tmpQuv)
+	assert.Equal(t, foo, getFieldByName(tmpQuv, passerFieldName))
+}
+
+func TestAutowireUnorderedInterfaceByType(t *testing.T) {
+	c := NewContainer()
+	tmpQuy := &fake.Quy{}
+	c.Autowire(tmpQuy)
+	assert.Nil(t, tmpQuy.Passer)
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	assert.Equal(t, foo, tmpQuy.Passer)
+}
+
+func TestAutowireInterfaceByTypeProvided(t *testing.T) {
+	c := NewContainer()
+	tmpQuy := &fake.Quy{}
+	c.Autowire(tmpQuy)
+	c.Provide(func() *fake.Foo { return &fake.Foo{Name: "provided"} })
+	assert.Equal(t, "provided", tmpQuy.Passer.(*fake.Foo).Name)
+}
+
+func TestAutowireInterfaceByTypeAmbiguous(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{}, &Foo{})
+	err := c.TryAutowire(&fake.Quy{})
+	assert.True(t, errors.Is(err, ErrAmbiguous))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "Passer", wiringErr.Field)
+	assert.Equal(t, []string{packageName + "/Foo", packageName + "/internal/fake/Foo"}, wiringErr.Candidates)
+}
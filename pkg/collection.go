@@ -0,0 +1,128 @@
+package pkg
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+// Ordered interface could be implemented by the structs injected into slice
+// fields, in order to control their position. Structs with lower order come
+// first, while structs with equal order or not implementing Ordered interface
+// are sorted by their registration order.
+type Ordered interface {
+	Order() int
+}
+
+// isCollection function reports whether the type is a slice of interfaces or a map
+// of interfaces keyed by string, which are populated with all the implementations.
+func isCollection(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Interface
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface
+	default:
+		return false
+	}
+}
+
+// autowireCollection method injects all implementations of the element interface
+// into the slice or map field. Slice is sorted according to Ordered interface and
+// registration order, while map is keyed by name of the struct or its full path.
+// The struct remains subscribed, so the collection is populated once again when
+// new implementation is registered. Implementations, whose constructor arguments aren't
+// registered yet, are injected once the arguments are registered. The caller is expected
+// to hold the lock.
+func (c *Container) autowireCollection(elem reflect.Value, i int, structType string) error {
+	fieldType := elem.Type().Field(i).Type
+	iface := fieldType.Elem()
+	var keys []string
+	var values []interface{}
+	for _, key := range c.findImplementations(iface) {
+		if key == structType {
+			continue
+		}
+		dependency, err := c.resolvePath(key)
+		if pending, ok := pendingDependency(err); ok {
+			c.markStructUninitialized(structType, pending,
+				unresolvedField{field: elem.Type().Field(i).Name, optional: true})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if cycle := c.addEdge(structType, key, false); cycle != nil {
+			return &WiringError{Err: ErrCircularDependency, Struct: structType,
+				Field: elem.Type().Field(i).Name, Cycle: cycle}
+		}
+		keys = append(keys, key)
+		values = append(values, dependency)
+	}
+	var collection reflect.Value
+	if fieldType.Kind() == reflect.Slice {
+		c.sortByOrder(keys, values)
+		collection = reflect.MakeSlice(fieldType, 0, len(values))
+		for _, dependency := range values {
+			collection = reflect.Append(collection, reflect.ValueOf(dependency))
+		}
+	} else {
+		collection = reflect.MakeMapWithSize(fieldType, len(values))
+		for j, dependency := range values {
+			collection.SetMapIndex(reflect.ValueOf(c.beanName(keys[j])).Convert(fieldType.Key()),
+				reflect.ValueOf(dependency))
+		}
+	}
+	internal.SetFieldValue(elem, i, collection.Interface())
+	if _, ok := c.collections[iface]; !ok {
+		c.collections[iface] = map[string]interface{}{}
+	}
+	c.collections[iface][structType] = true
+	return nil
+}
+
+// resolveCollections method populates once again collections of all the structs,
+// which are subscribed to the interface implemented by the dependency of type t.
+func (c *Container) resolveCollections(t reflect.Type) error {
+	for iface, subscribers := range c.collections {
+		if !t.Implements(iface) {
+			continue
+		}
+		for subscriber := range subscribers {
+			if dep, ok := c.dependencies[subscriber]; ok {
+				if err := c.autowireDependencies(reflect.ValueOf(dep), subscriber); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Container) sortByOrder(keys []string, values []interface{}) {
+	order := func(j int) int {
+		if ordered, ok := values[j].(Ordered); ok {
+			return ordered.Order()
+		}
+		return 0
+	}
+	indexes := make([]int, len(keys))
+	for j := range indexes {
+		indexes[j] = j
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		if order(indexes[a]) != order(indexes[b]) {
+			return order(indexes[a]) < order(indexes[b])
+		}
+		return c.sequence[keys[indexes[a]]] < c.sequence[keys[indexes[b]]]
+	})
+	sortedKeys := make([]string, len(keys))
+	sortedValues := make([]interface{}, len(values))
+	for j, index := range indexes {
+		sortedKeys[j] = keys[index]
+		sortedValues[j] = values[index]
+	}
+	copy(keys, sortedKeys)
+	copy(values, sortedValues)
+}
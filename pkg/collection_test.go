@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type plugin interface {
+	Plugin() string
+}
+
+type firstPlugin struct{}
+
+func (firstPlugin) Plugin() string { return "first" }
+
+type secondPlugin struct{}
+
+func (secondPlugin) Plugin() string { return "second" }
+
+type orderedPlugin struct{}
+
+func (orderedPlugin) Plugin() string { return "ordered" }
+
+func (orderedPlugin) Order() int { return -1 }
+
+type pluginRegistry struct {
+	Plugins []plugin          `autowire:""`
+	byName  map[string]plugin `autowire:""`
+}
+
+func (p *pluginRegistry) names() []string {
+	var result []string
+	for _, plugin := range p.Plugins {
+		result = append(result, plugin.Plugin())
+	}
+	return result
+}
+
+func Test_isCollection(t *testing.T) {
+	assert.True(t, isCollection(reflect.TypeOf([]plugin{})))
+	assert.True(t, isCollection(reflect.TypeOf(map[string]plugin{})))
+	assert.False(t, isCollection(reflect.TypeOf([]*firstPlugin{})))
+	assert.False(t, isCollection(reflect.TypeOf(map[int]plugin{})))
+	assert.False(t, isCollection(reflect.TypeOf(&firstPlugin{})))
+}
+
+func TestAutowireCollection(t *testing.T) {
+	c := NewContainer()
+	first := &firstPlugin{}
+	second := &secondPlugin{}
+	c.Autowire(second, first)
+	c.AutowireNamed("ordered", &orderedPlugin{})
+	c.Autowire(&fake.Foo{})
+	registry := &pluginRegistry{}
+	c.Autowire(registry)
+	assert.Equal(t, []string{"ordered", "second", "first"}, registry.names())
+	assert.Equal(t, 3, len(registry.byName))
+	assert.Equal(t, first, registry.byName[packageName+"/firstPlugin"])
+	assert.Equal(t, second, registry.byName[packageName+"/secondPlugin"])
+	assert.Equal(t, "ordered", registry.byName["ordered"].Plugin())
+}
+
+func TestAutowireCollectionEmpty(t *testing.T) {
+	c := NewContainer()
+	registry := &pluginRegistry{}
+	c.Autowire(registry)
+	assert.NotNil(t, registry.Plugins)
+	assert.Equal(t, 0, len(registry.Plugins))
+	assert.Equal(t, 0, len(registry.byName))
+}
+
+func TestAutowireCollectionRegisteredLater(t *testing.T) {
+	c := NewContainer()
+	registry := &pluginRegistry{}
+	c.Autowire(&firstPlugin{}, registry)
+	assert.Equal(t, []string{"first"}, registry.names())
+	c.Autowire(&secondPlugin{})
+	assert.Equal(t, []string{"first", "second"}, registry.names())
+	c.Autowire(&orderedPlugin{})
+	assert.Equal(t, []string{"ordered", "first", "second"}, registry.names())
+	assert.Equal(t, 3, len(registry.byName))
+}
+
+func TestAutowireCollectionProvided(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func() *secondPlugin { return &secondPlugin{} })
+	registry := &pluginRegistry{}
+	c.Autowire(registry)
+	assert.Equal(t, []string{"second"}, registry.names())
+	c.Provide(func() *firstPlugin { return &firstPlugin{} })
+	assert.Equal(t, []string{"second", "first"}, registry.names())
+}
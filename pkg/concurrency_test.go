@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+const goroutines = 64
+
+func TestConcurrentAutowire(t *testing.T) {
+	c := NewContainer()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				c.Autowire(&fake.Baz{}, &fake.Foo{})
+			} else {
+				c.Autowire(&fake.Foo{}, &fake.Bar{})
+			}
+		}(i)
+	}
+	wg.Wait()
+	foo := c.Autowired(fake.Foo{}).(*fake.Foo)
+	baz := c.Autowired(fake.Baz{}).(*fake.Baz)
+	bar := c.Autowired(fake.Bar{}).(*fake.Bar)
+	assert.Equal(t, foo, baz.MyFoo)
+	assert.Equal(t, foo, getFieldByName(bar, myFooFieldName))
+	assert.Equal(t, 0, len(c.Close()))
+}
+
+func TestConcurrentAutowireAndAutowired(t *testing.T) {
+	c := NewContainer()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Autowire(&fake.Qux{}, &fake.Baz{}, &fake.Foo{})
+		}()
+		go func() {
+			defer wg.Done()
+			if baz, ok := c.Autowired(&fake.Baz{}).(*fake.Baz); ok {
+				assert.NotNil(t, baz)
+			}
+			c.Autowired(fake.Qux{})
+		}()
+	}
+	wg.Wait()
+	assert.NotNil(t, c.Autowired(fake.Foo{}).(*fake.Foo))
+	assert.NotNil(t, c.Autowired(fake.Baz{}).(*fake.Baz).MyFoo)
+	assert.Equal(t, 0, len(c.Close()))
+}
+
+func TestConcurrentPendingResolution(t *testing.T) {
+	c := NewContainer()
+	var wg sync.WaitGroup
+	bars := make([]*fake.Bar, goroutines)
+	for i := 0; i < goroutines; i++ {
+		bars[i] = &fake.Bar{}
+	}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.Autowire(bars[i])
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.Autowire(&fake.Foo{})
+	}()
+	wg.Wait()
+	foo := c.Autowired(fake.Foo{})
+	registered := c.Autowired(fake.Bar{}).(*fake.Bar)
+	assert.Equal(t, foo, getFieldByName(registered, myFooFieldName))
+	assert.Equal(t, 0, len(c.Close()))
+}
+
+func TestConcurrentClose(t *testing.T) {
+	c := NewContainer()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			c.Autowire(&fake.Foo{}, &fake.Baz{})
+		}()
+		go func() {
+			defer wg.Done()
+			c.Autowired(fake.Baz{})
+		}()
+		go func() {
+			defer wg.Done()
+			c.Close()
+		}()
+	}
+	wg.Wait()
+	c.Close()
+	assert.Nil(t, c.Autowired(fake.Foo{}))
+}
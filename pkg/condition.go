@@ -0,0 +1,254 @@
+package pkg
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+// conditionPhase represents the order of evaluation of the conditions, so the
+// registrations depending on the presence of the beans are evaluated last.
+type conditionPhase int
+
+const (
+	environmentPhase conditionPhase = iota
+	beanPhase
+	missingBeanPhase
+)
+
+// Condition represents the condition of the registration passed to Register or
+// Provide function. Conditional registrations are deferred until Finalize function
+// is invoked, so the order of init functions doesn't matter:
+//  pkg.Register(&BankAccountService{}, pkg.OnProfile("prod"))
+//  pkg.Provide(NewMockPaymentService, pkg.OnMissingBean[PaymentService]())
+// Registration is performed only when all of its conditions match. Conditions
+// checking environment are evaluated first, followed by OnBean and OnMissingBean
+// conditions, so the registrations of OnMissingBean could act as defaults.
+type Condition struct {
+	description string
+	phase       conditionPhase
+	matches     func(c *Container) bool
+}
+
+func (cond Condition) apply(r *registration) {
+	r.conditions = append(r.conditions, cond)
+}
+
+// OnProfile function returns Condition matching when any of the profiles is active.
+// Profile prefixed with ! matches when the profile is not active, e.g. OnProfile("!test").
+func OnProfile(profiles ...string) Condition {
+	return Condition{description: "profile " + strings.Join(profiles, ","), phase: environmentPhase,
+		matches: func(c *Container) bool {
+			for _, profile := range profiles {
+				if strings.HasPrefix(profile, "!") != c.isProfileActive(strings.TrimPrefix(profile, "!")) {
+					return true
+				}
+			}
+			return false
+		}}
+}
+
+// OnProduction function returns Condition matching when the program isn't running
+// inside go tests, regardless of the active profiles, e.g. the replacement of RunProd:
+//  pkg.Register(&BankAccountService{}, pkg.OnProduction())
+func OnProduction() Condition {
+	return Condition{description: "production", phase: environmentPhase,
+		matches: func(*Container) bool {
+			return currentProfile == internal.Production
+		}}
+}
+
+// OnEnv function returns Condition matching when the environment variable equals the value.
+func OnEnv(key string, value string) Condition {
+	return Condition{description: "env " + key + "=" + value, phase: environmentPhase,
+		matches: func(*Container) bool {
+			actual, ok := os.LookupEnv(key)
+			return ok && actual == value
+		}}
+}
+
+// OnCondition function returns Condition matching when the predicate returns true.
+// The predicate is invoked while the Container is locked, so it shouldn't use the Container.
+func OnCondition(description string, predicate func() bool) Condition {
+	return Condition{description: description, phase: environmentPhase,
+		matches: func(*Container) bool {
+			return predicate()
+		}}
+}
+
+// OnBean function returns Condition matching when the dependency of type T is registered.
+// T could be either a struct pointer or an interface.
+func OnBean[T any]() Condition {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return Condition{description: "bean " + t.String(), phase: beanPhase,
+		matches: func(c *Container) bool {
+			return c.hasBean(t)
+		}}
+}
+
+// OnMissingBean function returns Condition matching when no dependency of type T is registered.
+// T could be either a struct pointer or an interface.
+func OnMissingBean[T any]() Condition {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return Condition{description: "missing bean " + t.String(), phase: missingBeanPhase,
+		matches: func(c *Container) bool {
+			return !c.hasBean(t)
+		}}
+}
+
+// pendingRegistration holds the conditional registration deferred until Finalize function.
+type pendingRegistration struct {
+	value      interface{}
+	provider   *provider
+	conditions []Condition
+}
+
+func (p pendingRegistration) phase() conditionPhase {
+	var phase conditionPhase
+	for _, cond := range p.conditions {
+		if cond.phase > phase {
+			phase = cond.phase
+		}
+	}
+	return phase
+}
+
+func (p pendingRegistration) path() string {
+	if p.provider != nil {
+		return getFullPath(p.provider.resultType.Elem().PkgPath(), p.provider.resultType.String())
+	}
+	return getStructPtrFullPath(reflect.ValueOf(p.value))
+}
+
+// Register function registers the struct inside the default Container like Autowire
+// function, but only when all the conditions match. For more information take a look
+// at Condition type. Register panics in case of failure.
+func Register(v interface{}, options ...Option) {
+	defaultContainer.Register(v, options...)
+}
+
+// TryRegister function works like Register function, but instead of panicking
+// returns *WiringError describing the failure.
+func TryRegister(v interface{}, options ...Option) error {
+	return defaultContainer.TryRegister(v, options...)
+}
+
+// Finalize function evaluates the conditions of all the conditional registrations
+// of the default Container and registers the matching ones. Registrations made after
+// Finalize function are evaluated immediately. Finalize function should be invoked
+// once all the init() functions have run, while Validate and Run functions invoke
+// it themselves. Finalize function is idempotent. Failed Init methods of Initializer
+// interface are reported as *ValidationError.
+func Finalize() error {
+	return defaultContainer.Finalize()
+}
+
+// Register method registers the struct inside the Container, when all the conditions
+// match. For more information take a look at Register function.
+func (c *Container) Register(v interface{}, options ...Option) {
+	if err := c.TryRegister(v, options...); err != nil {
+		log.Panicln(err.Error())
+	}
+}
+
+// TryRegister method works like Register method, but instead of panicking
+// returns *WiringError describing the failure.
+func (c *Container) TryRegister(v interface{}, options ...Option) error {
+	conditions := newRegistration(options).conditions
+	return c.update(func() error {
+		if c.deferRegistration(pendingRegistration{value: v, conditions: conditions}) {
+			return nil
+		}
+		return c.register(v, "")
+	})
+}
+
+// Finalize method evaluates the conditional registrations of the Container. For more
+// information take a look at Finalize function.
+func (c *Container) Finalize() error {
+	if err := c.finalize(); err != nil {
+		return err
+	}
+	return c.initFailure()
+}
+
+// finalize method registers the conditional registrations, which match.
+func (c *Container) finalize() error {
+	return c.update(func() error {
+		c.finalized = true
+		pending := c.pending
+		c.pending = nil
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].phase() < pending[j].phase()
+		})
+		for _, p := range pending {
+			if !c.conditionsMatch(p) {
+				continue
+			}
+			var err error
+			if p.provider != nil {
+				err = c.addProvider(p.provider)
+			} else {
+				err = c.register(p.value, "")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deferRegistration method defers the conditional registration until Finalize method.
+// Once finalized, the conditions are evaluated immediately. Returning false, when the
+// registration should be performed right away. The caller is expected to hold the lock.
+func (c *Container) deferRegistration(p pendingRegistration) bool {
+	if len(p.conditions) == 0 {
+		return false
+	}
+	if !c.finalized {
+		c.pending = append(c.pending, p)
+		return true
+	}
+	return !c.conditionsMatch(p)
+}
+
+// conditionsMatch method reports whether all the conditions of the registration match.
+func (c *Container) conditionsMatch(p pendingRegistration) bool {
+	for _, cond := range p.conditions {
+		if !cond.matches(c) {
+			log.Printf("%s skipped, condition %s doesn't match", p.path(), cond.description)
+			return false
+		}
+	}
+	return true
+}
+
+// hasBean method reports whether the dependency of type t is registered inside the
+// Container or its ancestors. The caller is expected to hold the lock.
+func (c *Container) hasBean(t reflect.Type) bool {
+	switch {
+	case t.Kind() == reflect.Interface:
+		if len(c.implementations(t, true)) > 0 {
+			return true
+		}
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		path := getFullPath(t.Elem().PkgPath(), t.String())
+		if _, ok := c.dependencies[path]; ok {
+			return true
+		}
+		if _, ok := c.providers[path]; ok {
+			return true
+		}
+	}
+	if c.parent == nil {
+		return false
+	}
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+	return c.parent.hasBean(t)
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gateway interface {
+	Charge() string
+}
+
+type cardGateway struct{}
+
+func (g *cardGateway) Charge() string {
+	return "card"
+}
+
+type mockGateway struct{}
+
+func (g *mockGateway) Charge() string {
+	return "mock"
+}
+
+type checkout struct {
+	Gateway gateway `autowire:""`
+}
+
+func newMockGateway() *mockGateway {
+	return &mockGateway{}
+}
+
+func TestRegisterOnProfile(t *testing.T) {
+	c := NewContainer()
+	c.Register(&cardGateway{}, OnProfile("!test"))
+	c.Provide(newMockGateway, OnMissingBean[gateway]())
+	assert.Equal(t, 2, len(c.pending))
+	assert.Nil(t, c.Finalize())
+	_, ok := GetFrom[*cardGateway](c)
+	assert.False(t, ok)
+	assert.Equal(t, "mock", MustGetFrom[gateway](c).Charge())
+}
+
+func TestOnMissingBeanEvaluatedLast(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.SetProfiles("prod"))
+	c.Provide(newMockGateway, OnMissingBean[gateway]())
+	c.Register(&cardGateway{}, OnProfile("prod"))
+	c.Autowire(&checkout{})
+	assert.Nil(t, c.Finalize())
+	assert.Equal(t, "card", MustGetFrom[*checkout](c).Gateway.Charge())
+	_, ok := GetFrom[*mockGateway](c)
+	assert.False(t, ok)
+}
+
+func TestRegisterAfterFinalize(t *testing.T) {
+	t.Setenv("FEATURE_X", "true")
+	c := NewContainer()
+	assert.Nil(t, c.Finalize())
+	c.Register(&cardGateway{}, OnEnv("FEATURE_X", "false"))
+	c.Register(&mockGateway{}, OnEnv("FEATURE_X", "true"), OnBean[*checkout]())
+	_, ok := GetFrom[*mockGateway](c)
+	assert.False(t, ok)
+	c.Autowire(&checkout{})
+	c.Register(&mockGateway{}, OnEnv("FEATURE_X", "true"), OnCondition("enabled", func() bool { return true }))
+	assert.Equal(t, "mock", MustGetFrom[*checkout](c).Gateway.Charge())
+	_, ok = GetFrom[*cardGateway](c)
+	assert.False(t, ok)
+}
+
+func TestOnBeanInParent(t *testing.T) {
+	parent := NewContainer()
+	parent.Autowire(&cardGateway{})
+	child := parent.NewChild()
+	child.Register(&mockGateway{}, OnMissingBean[gateway]())
+	child.Register(&checkout{}, OnBean[*cardGateway]())
+	assert.Nil(t, child.Finalize())
+	assert.Equal(t, "card", MustGetFrom[*checkout](child).Gateway.Charge())
+	_, ok := GetFrom[*mockGateway](child)
+	assert.False(t, ok)
+}
+
+func TestValidateFinalizes(t *testing.T) {
+	t.Setenv("FEATURE_X", "true")
+	c := NewContainer()
+	c.Autowire(&checkout{})
+	c.Register(&cardGateway{}, OnEnv("FEATURE_X", "true"))
+	assert.Nil(t, c.Validate())
+	assert.Equal(t, "card", MustGetFrom[*checkout](c).Gateway.Charge())
+}
+
+func TestOnProduction(t *testing.T) {
+	t.Setenv(ProfilesEnv, "prod")
+	c := NewContainer()
+	c.Register(&cardGateway{}, OnProduction())
+	c.Register(&mockGateway{}, OnProfile("!test"))
+	assert.Nil(t, c.Finalize())
+	_, ok := GetFrom[*cardGateway](c)
+	assert.False(t, ok)
+	_, ok = GetFrom[*mockGateway](c)
+	assert.True(t, ok)
+}
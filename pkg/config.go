@@ -0,0 +1,257 @@
+package pkg
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+const (
+	// configPrefix represents prefix of the autowire tag option holding the path of the configuration subtree.
+	configPrefix = "config="
+	// ConfigTag represents the tag overriding the name of the field bound to the configuration subtree.
+	ConfigTag = "config"
+)
+
+// LoadConfig function reads YAML, JSON or TOML configuration file into the default
+// Container, the format is recognized by the file extension. Properties of the file
+// are available by dot separated lowercase path, e.g. server.port, for the value tags:
+//  type ServerConfig struct {
+//      port int `value:"${server.port:8080}"`
+//  }
+// or could be bound to the whole struct using config option, either on the field:
+//  type Application struct {
+//      server *ServerConfig `autowire:",config=server"`
+//  }
+// or on the blank field of the registered struct itself:
+//  type ServerConfig struct {
+//      _       struct{}      `autowire:",config=server"`
+//      Port    int
+//      Timeout time.Duration `config:"read_timeout"`
+//  }
+// Fields are bound to the properties named after the field or its config tag, case
+// insensitively, while nested structs are bound to the nested subtrees. Properties are
+// layered with following precedence: file, environment variable named after the upper
+// cased property with dots replaced by underscores, e.g. SERVER_PORT, and the explicit
+// overrides set by SetProperty function. Loading the configuration fills once again all
+// the value and config fields of the registered structs, so it could be loaded at the
+// beginning of the main function, after the structs were registered in init functions.
+// Files loaded later override the properties of the ones loaded earlier, while overlays
+// of the active profiles are merged on top of each file, take a look at SetProfiles function.
+// Invalid configuration is reported as *WiringError caused by ErrInvalidConfig.
+func LoadConfig(path string) error {
+	return defaultContainer.LoadConfig(path)
+}
+
+// ReadConfig function works like LoadConfig function, but reads the configuration of
+// the given format (yaml, json or toml) from the reader.
+func ReadConfig(r io.Reader, format string) error {
+	return defaultContainer.ReadConfig(r, format)
+}
+
+// SetProperty function sets the property of the default Container, which takes precedence
+// over both configuration files and environment variables. For more information take a
+// look at LoadConfig function.
+func SetProperty(key string, value string) error {
+	return defaultContainer.SetProperty(key, value)
+}
+
+// LoadConfig method reads configuration file into the Container. For more information
+// take a look at LoadConfig function.
+func (c *Container) LoadConfig(path string) error {
+	properties, err := parseConfigFile(path)
+	if err != nil {
+		return &WiringError{Err: ErrInvalidConfig, Dependency: path, Cause: err}
+	}
+	return c.addConfigSource(configSource{path: path, properties: properties})
+}
+
+// ReadConfig method reads configuration of the given format from the reader into
+// the Container. For more information take a look at ReadConfig function.
+func (c *Container) ReadConfig(r io.Reader, format string) error {
+	properties, err := internal.ParseConfig(r, format)
+	if err != nil {
+		return &WiringError{Err: ErrInvalidConfig, Dependency: format, Cause: err}
+	}
+	return c.addConfigSource(configSource{properties: properties})
+}
+
+// SetProperty method sets the property of the Container. For more information take
+// a look at SetProperty function.
+func (c *Container) SetProperty(key string, value string) error {
+	return c.update(func() error {
+		c.overrides[strings.ToLower(key)] = value
+		return c.refreshValues()
+	})
+}
+
+// configSource holds properties read from the configuration file or reader.
+type configSource struct {
+	path       string
+	properties map[string]string
+}
+
+func parseConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+	return internal.ParseConfig(file, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+func (c *Container) addConfigSource(source configSource) error {
+	return c.update(func() error {
+		c.configSources = append(c.configSources, source)
+		if err := c.rebuildProperties(); err != nil {
+			return err
+		}
+		return c.refreshValues()
+	})
+}
+
+// rebuildProperties method merges the properties of all the configuration sources
+// together with overlays of the active profiles. The caller is expected to hold the lock.
+func (c *Container) rebuildProperties() error {
+	properties := map[string]string{}
+	for _, source := range c.configSources {
+		merged, err := c.loadOverlays(source)
+		if err != nil {
+			return err
+		}
+		for key, value := range merged {
+			properties[key] = value
+		}
+	}
+	c.properties = properties
+	return nil
+}
+
+// lookupProperty method returns the value of the configuration property, looking
+// it up in the overrides, environment variables and configuration files, while each
+// layer is looked up in the ancestors of the Container as well. The caller is expected
+// to hold the lock.
+func (c *Container) lookupProperty(key string) (string, bool) {
+	lower := strings.ToLower(key)
+	if value, ok := c.lookupLayer(lower, func(c *Container) map[string]string { return c.overrides }); ok {
+		return value, true
+	}
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	if value, ok := os.LookupEnv(envName(key)); ok {
+		return value, true
+	}
+	return c.lookupLayer(lower, func(c *Container) map[string]string { return c.properties })
+}
+
+// lookupLayer method returns the property of the layer of the Container or its ancestors.
+func (c *Container) lookupLayer(key string, layer func(c *Container) map[string]string) (string, bool) {
+	if value, ok := layer(c)[key]; ok {
+		return value, true
+	}
+	if c.parent == nil {
+		return "", false
+	}
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+	return c.parent.lookupLayer(key, layer)
+}
+
+// envName function returns the name of the environment variable overriding the property.
+func envName(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// injectConfig method binds the field to the configuration subtree under the prefix.
+// Blank field binds the struct itself. The caller is expected to hold the lock.
+func (c *Container) injectConfig(elem reflect.Value, i int, structType string, prefix string) error {
+	field := elem.Type().Field(i)
+	if field.Name == "_" {
+		return c.bindConfig(elem, structType, prefix)
+	}
+	value := reflect.ValueOf(internal.FieldAddr(elem, i)).Elem()
+	switch {
+	case field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct:
+		if value.IsNil() {
+			value.Set(reflect.New(field.Type.Elem()))
+		}
+		return c.bindConfig(value.Elem(), structType, prefix)
+	case internal.IsNested(field.Type):
+		return c.bindConfig(value, structType, prefix)
+	default:
+		return c.bindProperty(value, structType, field.Name, prefix)
+	}
+}
+
+// bindConfig method sets the fields of the struct to the properties under the prefix.
+func (c *Container) bindConfig(elem reflect.Value, structType string, prefix string) error {
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Type().Field(i)
+		if field.Name == "_" {
+			continue
+		}
+		name := field.Tag.Get(ConfigTag)
+		if name == "" {
+			name = field.Name
+		}
+		value := reflect.ValueOf(internal.FieldAddr(elem, i)).Elem()
+		var err error
+		if internal.IsNested(field.Type) {
+			err = c.bindConfig(value, structType, prefix+"."+name)
+		} else {
+			err = c.bindProperty(value, structType, field.Name, prefix+"."+name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindProperty method sets the value to the property, unless the property is missing.
+func (c *Container) bindProperty(value reflect.Value, structType string, fieldName string, key string) error {
+	raw, ok := c.lookupProperty(key)
+	if !ok {
+		return nil
+	}
+	converted, err := internal.ConvertValue(raw, value.Type())
+	if err != nil {
+		return &WiringError{Err: ErrInvalidValue, Struct: structType, Field: fieldName, Tag: key, Cause: err}
+	}
+	value.Set(converted)
+	return nil
+}
+
+// refreshValues method fills once again value and config fields of all the registered
+// structs with the current properties. The caller is expected to hold the lock.
+func (c *Container) refreshValues() error {
+	keys := make([]string, 0, len(c.dependencies))
+	for key := range c.dependencies {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.sequence[keys[i]] < c.sequence[keys[j]]
+	})
+	for _, key := range keys {
+		elem := reflect.ValueOf(c.dependencies[key]).Elem()
+		for i := 0; i < elem.NumField(); i++ {
+			field := elem.Type().Field(i)
+			var err error
+			if expression, ok := field.Tag.Lookup(ValueTag); ok {
+				err = c.injectValue(elem, i, key, expression)
+			} else if tag, ok := field.Tag.Lookup(Tag); ok && parseTag(tag).config != "" {
+				err = c.injectConfig(elem, i, key, parseTag(tag).config)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tlsConfig struct {
+	Enabled bool
+}
+
+type serverConfig struct {
+	_       struct{} `autowire:",config=server"`
+	Port    int
+	Timeout time.Duration `config:"read_timeout"`
+	hosts   []string
+	TLS     tlsConfig
+}
+
+type configService struct {
+	server *serverConfig `autowire:",config=server"`
+	port   int           `autowire:",config=server.port"`
+	apiKey string        `value:"${api.key:default}"`
+}
+
+func TestLoadConfig(t *testing.T) {
+	c := NewContainer()
+	server := &serverConfig{Port: 80}
+	service := &configService{}
+	c.Autowire(server, service)
+	assert.Equal(t, 80, server.Port)
+	assert.Equal(t, "default", service.apiKey)
+
+	assert.Nil(t, c.LoadConfig("testdata/application.yaml"))
+	assert.Equal(t, 8080, server.Port)
+	assert.Equal(t, 5*time.Second, server.Timeout)
+	assert.Equal(t, []string{"first", "second"}, server.hosts)
+	assert.True(t, server.TLS.Enabled)
+	assert.Equal(t, 8080, service.server.Port)
+	assert.NotSame(t, server, service.server)
+	assert.Equal(t, 8080, service.port)
+	assert.Equal(t, "file", service.apiKey)
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.ReadConfig(strings.NewReader(`{"server": {"port": 8080}, "api": {"key": "file"}}`), "json"))
+	server := &serverConfig{}
+	service := &configService{}
+	c.Autowire(server, service)
+	assert.Equal(t, 8080, server.Port)
+
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("API_KEY", "env")
+	assert.Nil(t, c.SetProperty("unrelated", ""))
+	assert.Equal(t, 9090, server.Port)
+	assert.Equal(t, "env", service.apiKey)
+
+	assert.Nil(t, c.SetProperty("server.port", "7070"))
+	assert.Equal(t, 7070, server.Port)
+	assert.Equal(t, 7070, service.port)
+	assert.Equal(t, "env", service.apiKey)
+
+	child := c.NewChild()
+	childService := &configService{}
+	child.Autowire(childService)
+	assert.Equal(t, 7070, childService.port)
+}
+
+func TestConfigErrors(t *testing.T) {
+	c := NewContainer()
+	err := c.LoadConfig("testdata/missing.yaml")
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	err = c.ReadConfig(strings.NewReader("{"), "json")
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	c.Autowire(&serverConfig{})
+	err = c.SetProperty("server.port", "http")
+	assert.True(t, errors.Is(err, ErrInvalidValue))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "Port", wiringErr.Field)
+	assert.Equal(t, "server.Port", wiringErr.Tag)
+}
+
+func TestSetPropertyResolvesMissingValue(t *testing.T) {
+	c := NewContainer()
+	config := &requiredValueConfig{}
+	c.Autowire(config)
+	assert.NotNil(t, c.Validate())
+	assert.Nil(t, c.SetProperty("AUTOWIRE_TEST_SECRET", "secret"))
+	assert.Equal(t, "secret", config.secret)
+	assert.Nil(t, c.Validate())
+}
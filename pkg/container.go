@@ -0,0 +1,620 @@
+package pkg
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+// Container holds a dependency graph. Each Container is fully isolated from
+// the others, so separate graphs could be built per tenant, per test or per
+// sub-application. The package level functions operate on a default Container.
+// Container is safe for concurrent use by multiple goroutines.
+type Container struct {
+	mu                   sync.RWMutex
+	dependencies         map[string]interface{}
+	requiredDependencies map[string]map[string]unresolvedFields
+	providers            map[string]*provider
+	constructors         map[string]*provider
+	names                map[string]string
+	interfaces           map[string]reflect.Type
+	collections          map[reflect.Type]map[string]interface{}
+	sequence             map[string]int
+	edges                map[string]map[string]bool
+	constructing         []string
+	scope                *Scope
+	parent               *Container
+	properties           map[string]string
+	overrides            map[string]string
+	configSources        []configSource
+	profiles             []string
+	cyclePolicy          CyclePolicy
+	shutdownTimeout      time.Duration
+	gracePeriod          time.Duration
+	initialized          map[string]initState
+	initErrors           []*WiringError
+	pending              []pendingRegistration
+	finalized            bool
+}
+
+// NewContainer function returns new empty Container.
+func NewContainer() *Container {
+	c := &Container{properties: make(map[string]string), overrides: make(map[string]string)}
+	c.reset()
+	return c
+}
+
+// Autowire method injects all dependencies for the given structures and
+// registers them inside the Container. For more information take a look at
+// Autowire function.
+func (c *Container) Autowire(values ...interface{}) {
+	if err := c.TryAutowire(values...); err != nil {
+		log.Panicln(err.Error())
+	}
+}
+
+// TryAutowire method works like Autowire method, but instead of panicking
+// returns *WiringError describing the failure.
+func (c *Container) TryAutowire(values ...interface{}) error {
+	return c.update(func() error {
+		for _, v := range values {
+			if err := c.register(v, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AutowireNamed method injects all dependencies for the given structure and
+// registers it inside the Container under the name. For more information take
+// a look at AutowireNamed function.
+func (c *Container) AutowireNamed(name string, v interface{}) {
+	if err := c.TryAutowireNamed(name, v); err != nil {
+		log.Panicln(err.Error())
+	}
+}
+
+// TryAutowireNamed method works like AutowireNamed method, but instead of panicking
+// returns *WiringError describing the failure.
+func (c *Container) TryAutowireNamed(name string, v interface{}) error {
+	return c.update(func() error {
+		return c.register(v, name)
+	})
+}
+
+// Autowired method returns fully initialized instance registered inside the Container.
+// For more information take a look at Autowired function.
+func (c *Container) Autowired(v interface{}) interface{} {
+	dependency, err := c.TryAutowired(v)
+	if errors.Is(err, ErrNotPointer) {
+		log.Panicln("Unknown Autowired Typed!")
+	}
+	return dependency
+}
+
+// TryAutowired method works like Autowired method, but instead of panicking or
+// returning nil it returns *WiringError describing the failure.
+func (c *Container) TryAutowired(v interface{}) (interface{}, error) {
+	value := reflect.ValueOf(v)
+	var path string
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.String:
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		if key, ok := c.names[value.String()]; ok {
+			return c.dependencies[key], nil
+		}
+		if c.parent != nil {
+			return c.parent.TryAutowired(v)
+		}
+		return nil, &WiringError{Err: ErrNotFound, Dependency: value.String()}
+	case reflect.Struct:
+		path = getFullPath(value.Type().PkgPath(), value.Type().String())
+	case reflect.Ptr:
+		path = getStructPtrFullPath(value)
+	default:
+		return nil, &WiringError{Err: ErrNotPointer, Dependency: typeName(value)}
+	}
+	c.mu.RLock()
+	dependency, ok := c.dependencies[path]
+	c.mu.RUnlock()
+	if ok {
+		return dependency, nil
+	}
+	err := c.update(func() (err error) {
+		dependency, err = c.resolvePath(path)
+		return err
+	})
+	if c.parent != nil && isMissing(err, path) {
+		return c.parent.TryAutowired(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// lookupType method returns the dependency assignable to the given type. In
+// case of struct pointer the dependency is looked up by its full path, while
+// in case of interface the single registered implementation is returned.
+func (c *Container) lookupType(t reflect.Type) (interface{}, error) {
+	var dependency interface{}
+	err := c.update(func() (err error) {
+		_, dependency, err = c.resolveType(t)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// resolveType method returns the key and the dependency assignable to the given type.
+func (c *Container) resolveType(t reflect.Type) (string, interface{}, error) {
+	var key string
+	switch {
+	case t == contextType && c.scope != nil:
+		return "", c.scope.ctx, nil
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		key = getFullPath(t.Elem().PkgPath(), t.String())
+	case t.Kind() == reflect.Interface:
+		paths := c.findImplementations(t)
+		switch len(paths) {
+		case 0:
+			if c.parent != nil {
+				return c.parent.resolveInherited(t, tagOptions{})
+			}
+			return "", nil, &WiringError{Err: ErrNotFound, Dependency: t.String()}
+		case 1:
+			key = paths[0]
+		default:
+			return "", nil, &WiringError{Err: ErrAmbiguous, Dependency: t.String(), Candidates: paths}
+		}
+	default:
+		return "", nil, &WiringError{Err: ErrNotPointer, Dependency: t.String()}
+	}
+	dependency, err := c.resolvePath(key)
+	if c.parent != nil && isMissing(err, key) {
+		return c.parent.resolveInherited(t, tagOptions{})
+	}
+	return key, dependency, err
+}
+
+// Close method invoke Close method on each struct registered inside the Container,
+// which implements io.Closer interface. For more information take a look at Close function.
+func (c *Container) Close() []error {
+	log.Println("Closing...")
+	c.mu.Lock()
+	levels := c.dependencyLevels()
+	dependencies := c.dependencies
+	c.reset()
+	c.mu.Unlock()
+	var errors []error
+	for i := len(levels) - 1; i >= 0; i-- {
+		for j := len(levels[i]) - 1; j >= 0; j-- {
+			if err := closeDependency(levels[i][j], dependencies[levels[i][j]]); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+	return errors
+}
+
+// reset method cleans the dependency graph. The caller is expected to hold the lock.
+func (c *Container) reset() {
+	c.dependencies = make(map[string]interface{})
+	c.requiredDependencies = make(map[string]map[string]unresolvedFields)
+	c.providers = make(map[string]*provider)
+	c.constructors = make(map[string]*provider)
+	c.names = make(map[string]string)
+	c.interfaces = make(map[string]reflect.Type)
+	c.collections = make(map[reflect.Type]map[string]interface{})
+	c.sequence = make(map[string]int)
+	c.edges = make(map[string]map[string]bool)
+	c.initialized = make(map[string]initState)
+	c.initErrors = nil
+	c.pending = nil
+	c.finalized = false
+}
+
+// update method invokes the function holding the lock and afterwards
+// initializes all the structs, which became fully wired. Failures of Init
+// methods aren't returned, as they're reported by Validate method.
+func (c *Container) update(f func() error) error {
+	err := func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return f()
+	}()
+	if err != nil {
+		return err
+	}
+	c.initialize()
+	return nil
+}
+
+// unresolvedField describes struct field waiting for its dependency.
+type unresolvedField struct {
+	field    string
+	tag      string
+	optional bool
+}
+
+// unresolvedFields maps the name of the field to its description.
+type unresolvedFields map[string]unresolvedField
+
+// register, autowire, autowireDependencies, autowireField, resolveType, resolvePath,
+// markStructUninitialized and findDependency methods expect the caller to hold the lock.
+func (c *Container) register(v interface{}, name string) error {
+	key, err := c.autowire(v, name)
+	if err != nil {
+		return err
+	}
+	if err = c.resolveUninitialized(key, name, reflect.TypeOf(v)); err != nil {
+		return err
+	}
+	return c.resolveCollections(reflect.TypeOf(v))
+}
+
+// resolveUninitialized method autowires once again all the structs, which were waiting for the dependency of type t.
+func (c *Container) resolveUninitialized(key string, name string, t reflect.Type) error {
+	for depName, uncompletedDepMap := range c.requiredDependencies {
+		if !c.satisfies(key, name, t, depName) {
+			continue
+		}
+		for uncompleted := range uncompletedDepMap {
+			if dep, ok := c.dependencies[uncompleted]; ok {
+				delete(uncompletedDepMap, uncompleted)
+				if err := c.autowireDependencies(reflect.ValueOf(dep), uncompleted); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// satisfies method reports whether the dependency of type t registered under the key and
+// the name satisfies the struct waiting for the depName. The depName is either a
+// tag qualifier, name= option or full path of the interface.
+func (c *Container) satisfies(key string, name string, t reflect.Type, depName string) bool {
+	if iface, ok := c.interfaces[depName]; ok {
+		return t.Implements(iface)
+	}
+	return matchesTag(key, depName) || (name != "" && depName == namePrefix+name)
+}
+
+// autowire method injects dependencies of the struct and registers it under
+// the key. Registration key is the full path of the struct, while named
+// structs are registered under the full path followed by # and the name.
+func (c *Container) autowire(v interface{}, name string) (string, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return "", &WiringError{Err: ErrNotPointer, Dependency: typeName(value)}
+	}
+	key := getStructPtrFullPath(value)
+	if name != "" {
+		if registered, ok := c.names[name]; ok {
+			log.Printf("%s already autowired as %s... ignored", name, registered)
+			return registered, nil
+		}
+		key += "#" + name
+	}
+	if _, ok := c.dependencies[key]; ok {
+		log.Printf("%s already autowired... ignored", key)
+		return key, nil
+	}
+	log.Printf("Autowiring %s", key)
+	snapshot := reflect.New(value.Elem().Type()).Elem()
+	snapshot.Set(value.Elem())
+	err := c.autowireDependencies(value, key)
+	if err == nil {
+		err = c.rejectedCycle(key, name, value.Type())
+	}
+	if err != nil {
+		c.rollback(key, value, snapshot)
+		return "", err
+	}
+	c.dependencies[key] = v
+	c.addSequence(key)
+	if name != "" {
+		c.names[name] = key
+	}
+	return key, nil
+}
+
+// rollback method restores the fields of the struct, which failed to be registered
+// under the key, and forgets its edges and fields waiting for the dependencies.
+func (c *Container) rollback(key string, value reflect.Value, snapshot reflect.Value) {
+	value.Elem().Set(snapshot)
+	delete(c.edges, key)
+	for _, depMap := range c.requiredDependencies {
+		delete(depMap, key)
+	}
+	for _, subscribers := range c.collections {
+		delete(subscribers, key)
+	}
+}
+
+// resolvePath method returns dependency registered under the path. When only
+// a provider is registered under the path, the dependency is constructed and
+// registered first, unless the provider has Prototype lifetime, in which case
+// fresh instance is returned each time, or Scoped lifetime, in which case the
+// instance of the current scope is returned. Singletons are constructed outside
+// of the current scope, so the Scoped dependencies couldn't leak into them.
+func (c *Container) resolvePath(path string) (interface{}, error) {
+	if dependency, ok := c.dependencies[path]; ok {
+		return dependency, nil
+	}
+	if c.scope != nil {
+		if instance, ok := c.scope.instances[path]; ok {
+			return instance, nil
+		}
+	}
+	p, ok := c.providers[path]
+	if !ok {
+		return nil, &WiringError{Err: ErrNotFound, Dependency: path}
+	}
+	switch p.lifetime {
+	case Prototype:
+		return c.newInstance(path, nil)
+	case Scoped:
+		return c.resolveScoped(path)
+	}
+	if err := c.pushConstructing(path); err != nil {
+		return nil, err
+	}
+	defer c.popConstructing()
+	scope := c.scope
+	c.scope = nil
+	defer func() {
+		c.scope = scope
+	}()
+	dependency, err := c.construct(p)
+	if err != nil {
+		return nil, err
+	}
+	delete(c.providers, path)
+	if err = c.register(dependency, ""); err != nil {
+		c.providers[path] = p
+		return nil, err
+	}
+	return dependency, nil
+}
+
+func (c *Container) autowireDependencies(value reflect.Value, structType string) error {
+	elem := value.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		if expression, ok := elem.Type().Field(i).Tag.Lookup(ValueTag); ok {
+			if err := c.injectValue(elem, i, structType, expression); err != nil {
+				return err
+			}
+			continue
+		}
+		tag, ok := elem.Type().Field(i).Tag.Lookup(Tag)
+		if ok {
+			if err := c.autowireField(elem, i, structType, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Container) autowireField(elem reflect.Value, i int, structType string, tag string) error {
+	field := elem.Type().Field(i)
+	options := parseTag(tag)
+	if c.bindField(elem, i, options) {
+		return nil
+	}
+	if options.config != "" {
+		return c.injectConfig(elem, i, structType, options.config)
+	}
+	var candidates []string
+	var depName string
+	switch {
+	case options.name != "":
+		if key, ok := c.names[options.name]; ok {
+			candidates = []string{key}
+		}
+		depName = namePrefix + options.name
+	case options.qualifier != "":
+		candidates = c.findDependency(options.qualifier)
+		depName = options.qualifier
+	case isCollection(field.Type):
+		return c.autowireCollection(elem, i, structType)
+	case field.Type.Kind() == reflect.Interface:
+		candidates = c.findImplementations(field.Type)
+		depName = getFullPath(field.Type.PkgPath(), field.Type.String())
+		if len(candidates) == 0 {
+			c.interfaces[depName] = field.Type
+		}
+	default:
+		return c.injectPath(elem, i, structType, tag, getStructPtrFullPath(reflect.New(field.Type.Elem())))
+	}
+	switch len(candidates) {
+	case 0:
+		if inherited, err := c.inherit(elem, i, structType, tag, depName); inherited || err != nil {
+			return err
+		}
+		c.logMissing(depName, options)
+		c.markStructUninitialized(structType, depName,
+			unresolvedField{field: field.Name, tag: tag, optional: options.optional})
+	case 1:
+		return c.injectPath(elem, i, structType, tag, candidates[0])
+	default:
+		return &WiringError{Err: ErrAmbiguous, Struct: structType, Field: field.Name,
+			Tag: tag, Candidates: candidates}
+	}
+	return nil
+}
+
+// injectPath method injects the dependency registered under the path into the field. Fields
+// holding fresh or prototype instances are injected only once. In case the dependency or
+// any of its constructor arguments is missing, the struct waits for it.
+func (c *Container) injectPath(elem reflect.Value, i int, structType string, tag string, path string) error {
+	field := elem.Type().Field(i)
+	options := parseTag(tag)
+	if (options.fresh || c.isPrototype(path)) && !elem.Field(i).IsZero() {
+		return nil
+	}
+	dependency, err := c.resolveField(path, field.Type, options)
+	if err == nil {
+		if t := reflect.TypeOf(dependency); !t.AssignableTo(field.Type) {
+			return &WiringError{Err: ErrInterfaceMismatch, Struct: structType,
+				Field: field.Name, Tag: tag, Dependency: t.String()}
+		}
+		return c.inject(elem, i, structType, tag, path, dependency)
+	}
+	pending, ok := pendingDependency(err)
+	if !ok {
+		return err
+	}
+	if isMissing(err, path) {
+		if inherited, err := c.inherit(elem, i, structType, tag, pending); inherited || err != nil {
+			return err
+		}
+	}
+	c.markStructUninitialized(structType, pending,
+		unresolvedField{field: field.Name, tag: tag, optional: options.optional})
+	return nil
+}
+
+// resolveField method returns the dependency registered under the path, which is a new
+// instance in case of new option. The field type t is used to create new instance of
+// the struct, which isn't registered.
+func (c *Container) resolveField(path string, t reflect.Type, options tagOptions) (interface{}, error) {
+	if !options.fresh {
+		return c.resolvePath(path)
+	}
+	if t.Kind() != reflect.Ptr {
+		t = nil
+	}
+	return c.newInstance(path, t)
+}
+
+// logMissing method logs the dependency, which couldn't be found, unless it's optional.
+func (c *Container) logMissing(depName string, options tagOptions) {
+	if options.optional {
+		return
+	}
+	msg := "Unknown dependency " + depName + " found none"
+	if _, ok := c.interfaces[depName]; ok {
+		msg = "No implementation of " + depName + " found"
+	}
+	if currentProfile != internal.Testing {
+		log.Println(msg)
+	} else {
+		log.Println(msg + ", ready for spy")
+	}
+}
+
+// inject method sets the dependency registered under the key as the value of
+// the field, unless it closes a cycle rejected by the CyclePolicy.
+func (c *Container) inject(elem reflect.Value, i int, structType string, tag string,
+	key string, dependency interface{}) error {
+	if cycle := c.addEdge(structType, key, parseTag(tag).lazy); cycle != nil {
+		return &WiringError{Err: ErrCircularDependency, Struct: structType,
+			Field: elem.Type().Field(i).Name, Tag: tag, Cycle: cycle}
+	}
+	internal.SetFieldValue(elem, i, dependency)
+	return nil
+}
+
+func (c *Container) markStructUninitialized(structType string, depName string, field unresolvedField) {
+	depMap, ok := c.requiredDependencies[depName]
+	if !ok {
+		depMap = map[string]unresolvedFields{}
+		c.requiredDependencies[depName] = depMap
+	}
+	if _, ok = depMap[structType]; !ok {
+		depMap[structType] = unresolvedFields{}
+	}
+	depMap[structType][field.field] = field
+}
+
+// findDependency method returns sorted full paths of registered dependencies and providers matching the tag.
+func (c *Container) findDependency(tagDependencyType string) []string {
+	var result []string
+	for tmp := range c.dependencies {
+		if matchesTag(tmp, tagDependencyType) {
+			result = append(result, tmp)
+		}
+	}
+	for tmp := range c.providers {
+		if matchesTag(tmp, tagDependencyType) {
+			result = append(result, tmp)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// findImplementations method returns sorted full paths of all dependencies implementing the interface.
+// Dependencies provided with Scoped lifetime are skipped, unless the Scope is active.
+func (c *Container) findImplementations(iface reflect.Type) []string {
+	return c.implementations(iface, c.scope != nil)
+}
+
+// implementations method returns sorted full paths of all dependencies implementing the interface,
+// including the ones provided with Scoped lifetime, when scoped is true.
+func (c *Container) implementations(iface reflect.Type, scoped bool) []string {
+	var result []string
+	for path, dep := range c.dependencies {
+		if reflect.TypeOf(dep).Implements(iface) {
+			result = append(result, path)
+		}
+	}
+	for path, p := range c.providers {
+		if p.resultType.Implements(iface) && (scoped || p.lifetime != Scoped) {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// addSequence method remembers the registration order of the key.
+func (c *Container) addSequence(key string) {
+	if _, ok := c.sequence[key]; !ok {
+		c.sequence[key] = len(c.sequence)
+	}
+}
+
+// beanName method returns name of the dependency registered under the key or its full path for unnamed ones.
+func (c *Container) beanName(key string) string {
+	for name, registered := range c.names {
+		if registered == key {
+			return name
+		}
+	}
+	return key
+}
+
+// matchesTag function reports whether the full path of the dependency matches the tag.
+// The tag matches when it is equal to the full path or to its suffix, which starts
+// right after a path separator, e.g. "service/AuditService" matches
+// "github.com/project/service/AuditService", but neither "service/AuditServiceV2"
+// nor "otherservice/AuditService".
+func matchesTag(path string, tag string) bool {
+	return path == tag || strings.HasSuffix(path, "/"+tag)
+}
+
+// isFieldZero function reports whether the field of the struct pointer holds zero value.
+func isFieldZero(v interface{}, fieldName string) bool {
+	return reflect.ValueOf(v).Elem().FieldByName(fieldName).IsZero()
+}
+
+func typeName(value reflect.Value) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+	return value.Type().String()
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContainer(t *testing.T) {
+	c := NewContainer()
+	assert.NotNil(t, c.dependencies)
+	assert.NotNil(t, c.requiredDependencies)
+	assert.Nil(t, c.Autowired(fake.Foo{}))
+}
+
+func TestContainerAutowire(t *testing.T) {
+	c := NewContainer()
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	tmpBaz := &fake.Baz{}
+	c.Autowire(tmpBaz)
+	assert.Equal(t, foo, tmpBaz.MyFoo)
+	assert.Equal(t, tmpBaz, c.Autowired(fake.Baz{}))
+	assert.Equal(t, 0, len(c.Close()))
+	assert.Equal(t, 1, foo.CloseCalls)
+	assert.Nil(t, c.Autowired(fake.Baz{}))
+}
+
+func TestContainerIsolation(t *testing.T) {
+	first := NewContainer()
+	second := NewContainer()
+	firstFoo := &fake.Foo{Name: "first"}
+	secondFoo := &fake.Foo{Name: "second"}
+	first.Autowire(firstFoo)
+	second.Autowire(secondFoo)
+	assert.Equal(t, firstFoo, first.Autowired(fake.Foo{}))
+	assert.Equal(t, secondFoo, second.Autowired(fake.Foo{}))
+	assert.Nil(t, Autowired(fake.Foo{}))
+
+	tmpBaz := &fake.Baz{}
+	second.Autowire(tmpBaz)
+	assert.Equal(t, secondFoo, tmpBaz.MyFoo)
+	assert.Nil(t, first.Autowired(fake.Baz{}))
+
+	assert.Equal(t, 0, len(first.Close()))
+	assert.Equal(t, 1, firstFoo.CloseCalls)
+	assert.Equal(t, 0, secondFoo.CloseCalls)
+	assert.Equal(t, secondFoo, second.Autowired(fake.Foo{}))
+	assert.Equal(t, 0, len(second.Close()))
+}
+
+func TestContainerUnorderedDependencies(t *testing.T) {
+	c := NewContainer()
+	tmpBar := &fake.Bar{}
+	c.Autowire(tmpBar, &fake.Foo{})
+	assert.NotNil(t, getFieldByName(tmpBar, myFooFieldName))
+	assert.Equal(t, 0, len(c.requiredDependencies[packageName+"/internal/fake/Foo"]))
+}
+
+type Foo struct{}
+
+// Pass method
+func (Foo) Pass() {
+}
+
+type ambiguousPasser struct {
+	Passer fake.Passer `autowire:"Foo"`
+}
+
+func Test_matchesTag(t *testing.T) {
+	path := "github.com/project/service/AuditService"
+	assert.True(t, matchesTag(path, path))
+	assert.True(t, matchesTag(path, "service/AuditService"))
+	assert.True(t, matchesTag(path, "AuditService"))
+	assert.True(t, matchesTag(path, "project/service/AuditService"))
+	assert.False(t, matchesTag("github.com/project/service/AuditServiceV2", "service/AuditService"))
+	assert.False(t, matchesTag("github.com/project/otherservice/AuditService", "service/AuditService"))
+	assert.False(t, matchesTag(path, "Service"))
+	assert.False(t, matchesTag(path, "service"))
+}
+
+func TestContainerFindDependencyExact(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{}, &Foo{})
+	assert.Equal(t, []string{packageName + "/internal/fake/Foo"}, c.findDependency("fake/Foo"))
+	assert.Equal(t, []string{packageName + "/Foo"}, c.findDependency("pkg/Foo"))
+	assert.Equal(t, []string{packageName + "/Foo", packageName + "/internal/fake/Foo"}, c.findDependency("Foo"))
+	assert.Equal(t, 0, len(c.findDependency("oo")))
+}
+
+func TestContainerAmbiguousTag(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{}, &Foo{})
+	err := c.TryAutowire(&ambiguousPasser{})
+	assert.True(t, errors.Is(err, ErrAmbiguous))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "Passer", wiringErr.Field)
+	assert.Equal(t, []string{packageName + "/Foo", packageName + "/internal/fake/Foo"}, wiringErr.Candidates)
+}
@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CyclePolicy represents the way circular dependencies are handled, e.g.
+// app/Application -> service/UserService -> app/Application.
+// Circular dependencies between constructors are always rejected, as they could never be satisfied.
+type CyclePolicy int
+
+const (
+	// AllowCycles policy allows circular dependencies between struct fields.
+	AllowCycles CyclePolicy = iota
+	// RejectCycles policy rejects any circular dependency.
+	RejectCycles
+	// AllowLazyCycles policy allows circular dependency only in case at least one
+	// of the struct fields forming the cycle is marked with lazy option.
+	AllowLazyCycles
+)
+
+// lazyOption represents autowire tag option marking the field allowed to close a cycle.
+const lazyOption = "lazy"
+
+// SetCyclePolicy function sets the policy of handling circular dependencies inside
+// the default Container. AllowCycles is the default policy. Circular dependency
+// rejected by the policy is reported by Autowire function as *WiringError caused by
+// ErrCircularDependency containing the whole cycle, e.g.:
+//  autowire: circular dependency, ..., cycle app/Application -> service/UserService -> app/Application
+// The struct closing the rejected cycle isn't registered and its fields are left untouched.
+// The preferred way of breaking the cycle is Lazy field, which is resolved on the
+// first use, so it doesn't form a dependency and is accepted by any policy:
+//  type UserService struct {
+//      app pkg.Lazy[*Application] `autowire:""`
+//  }
+// Field marked with lazy option is still injected eagerly, the option only permits
+// the field to close the cycle under AllowLazyCycles policy:
+//  type UserService struct {
+//      app *Application `autowire:",lazy"`
+//  }
+func SetCyclePolicy(policy CyclePolicy) {
+	defaultContainer.SetCyclePolicy(policy)
+}
+
+// SetCyclePolicy method sets the policy of handling circular dependencies inside
+// the Container. For more information take a look at SetCyclePolicy function.
+func (c *Container) SetCyclePolicy(policy CyclePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cyclePolicy = policy
+}
+
+// addEdge method records that the dependency registered under the from key
+// depends on the one registered under the to key. In case the edge closes a
+// cycle rejected by the policy, the edge is not recorded and the cycle is returned.
+// The caller is expected to hold the lock.
+func (c *Container) addEdge(from string, to string, lazy bool) []string {
+	if c.cyclePolicy != AllowCycles {
+		if cycle := c.findPath(to, from, map[string]bool{}); cycle != nil {
+			cycle = append([]string{from}, cycle...)
+			if c.isRejected(cycle, lazy) {
+				return cycle
+			}
+		}
+	}
+	if _, ok := c.edges[from]; !ok {
+		c.edges[from] = map[string]bool{}
+	}
+	c.edges[from][to] = lazy
+	return nil
+}
+
+// isRejected method reports whether the CyclePolicy rejects the cycle closed by the
+// edge between the first two keys of the cycle.
+func (c *Container) isRejected(cycle []string, lazy bool) bool {
+	return c.cyclePolicy == RejectCycles || !(lazy || c.isLazyPath(cycle[1:]))
+}
+
+// rejectedCycle method returns *WiringError describing the cycle, which would be closed by
+// injecting the dependency registered under the key into the structs waiting for it, in case
+// the cycle is rejected by the CyclePolicy. This way the dependency could be rejected before
+// it's registered. The caller is expected to hold the lock.
+func (c *Container) rejectedCycle(key string, name string, t reflect.Type) error {
+	if c.cyclePolicy == AllowCycles {
+		return nil
+	}
+	for depName, depMap := range c.requiredDependencies {
+		if !c.satisfies(key, name, t, depName) {
+			continue
+		}
+		for structType, fields := range depMap {
+			if _, ok := c.dependencies[structType]; !ok && structType != key {
+				continue
+			}
+			path := c.findPath(key, structType, map[string]bool{})
+			if path == nil {
+				continue
+			}
+			cycle := append([]string{structType}, path...)
+			for _, field := range fields {
+				if c.isRejected(cycle, parseTag(field.tag).lazy) {
+					return &WiringError{Err: ErrCircularDependency, Struct: structType,
+						Field: field.field, Tag: field.tag, Cycle: cycle}
+				}
+			}
+		}
+	}
+	for iface, subscribers := range c.collections {
+		if !t.Implements(iface) {
+			continue
+		}
+		for subscriber := range subscribers {
+			if path := c.findPath(key, subscriber, map[string]bool{}); path != nil && subscriber != key {
+				if cycle := append([]string{subscriber}, path...); c.isRejected(cycle, false) {
+					return &WiringError{Err: ErrCircularDependency, Struct: subscriber, Cycle: cycle}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findPath method returns keys on the path between from and to keys following the edges.
+func (c *Container) findPath(from string, to string, visited map[string]bool) []string {
+	if from == to {
+		return []string{to}
+	}
+	visited[from] = true
+	for next := range c.edges[from] {
+		if visited[next] {
+			continue
+		}
+		if path := c.findPath(next, to, visited); path != nil {
+			return append([]string{from}, path...)
+		}
+	}
+	return nil
+}
+
+// isLazyPath method reports whether any of the edges on the path is lazy.
+func (c *Container) isLazyPath(path []string) bool {
+	for i := 1; i < len(path); i++ {
+		if c.edges[path[i-1]][path[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCycle function returns the cycle with shortened paths, e.g.
+// app/Application -> service/UserService -> app/Application.
+func formatCycle(cycle []string) string {
+	short := make([]string, len(cycle))
+	for i, key := range cycle {
+		short[i] = key
+		if last := strings.LastIndex(key, "/"); last > 0 {
+			if prev := strings.LastIndex(key[:last], "/"); prev >= 0 {
+				short[i] = key[prev+1:]
+			}
+		}
+	}
+	return strings.Join(short, " -> ")
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cycleA struct {
+	B *cycleB `autowire:""`
+}
+
+type cycleB struct {
+	A *cycleA `autowire:""`
+}
+
+type lazyCycleA struct {
+	B *lazyCycleB `autowire:""`
+}
+
+type lazyCycleB struct {
+	A *lazyCycleA `autowire:",lazy"`
+}
+
+type selfCycle struct {
+	Self *selfCycle `autowire:""`
+}
+
+type constructedA struct{}
+
+type constructedB struct{}
+
+func Test_formatCycle(t *testing.T) {
+	assert.Equal(t, "app/Application -> service/UserService -> app/Application", formatCycle([]string{
+		"github.com/project/app/Application",
+		"github.com/project/service/UserService",
+		"github.com/project/app/Application",
+	}))
+	assert.Equal(t, "Application -> Application", formatCycle([]string{"Application", "Application"}))
+}
+
+func TestCycleAllowed(t *testing.T) {
+	c := NewContainer()
+	a := &cycleA{}
+	b := &cycleB{}
+	c.Autowire(a, b)
+	assert.Equal(t, b, a.B)
+	assert.Equal(t, a, b.A)
+	assert.Nil(t, c.Validate())
+}
+
+func TestCycleRejected(t *testing.T) {
+	c := NewContainer()
+	c.SetCyclePolicy(RejectCycles)
+	a := &cycleA{}
+	c.Autowire(a)
+	err := c.TryAutowire(&cycleB{})
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, []string{packageName + "/cycleA", packageName + "/cycleB", packageName + "/cycleA"},
+		wiringErr.Cycle)
+	assert.Contains(t, err.Error(), "cycle pkg/cycleA -> pkg/cycleB -> pkg/cycleA")
+	assert.Nil(t, a.B)
+	_, ok := GetFrom[*cycleB](c)
+	assert.False(t, ok)
+	b := &cycleB{}
+	assert.NotNil(t, c.TryAutowire(b))
+	assert.Nil(t, b.A)
+	assert.True(t, errors.Is(c.Validate(), ErrNotFound))
+
+	self := NewContainer()
+	self.SetCyclePolicy(RejectCycles)
+	err = self.TryAutowire(&selfCycle{})
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, []string{packageName + "/selfCycle", packageName + "/selfCycle"}, wiringErr.Cycle)
+}
+
+func TestCycleRejectedLazy(t *testing.T) {
+	c := NewContainer()
+	c.SetCyclePolicy(RejectCycles)
+	c.Autowire(&lazyCycleA{})
+	assert.True(t, errors.Is(c.TryAutowire(&lazyCycleB{}), ErrCircularDependency))
+}
+
+func TestCycleAllowedLazy(t *testing.T) {
+	c := NewContainer()
+	c.SetCyclePolicy(AllowLazyCycles)
+	a := &lazyCycleA{}
+	b := &lazyCycleB{}
+	c.Autowire(a, b)
+	assert.Equal(t, b, a.B)
+	assert.Equal(t, a, b.A)
+
+	c = NewContainer()
+	c.SetCyclePolicy(AllowLazyCycles)
+	c.Autowire(&cycleA{})
+	assert.True(t, errors.Is(c.TryAutowire(&cycleB{}), ErrCircularDependency))
+}
+
+func TestCycleConstructors(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func(b *constructedB) *constructedA { return &constructedA{} })
+	c.Provide(func(a *constructedA) *constructedB { return &constructedB{} })
+	_, err := c.TryAutowired(constructedA{})
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, []string{packageName + "/constructedA", packageName + "/constructedB", packageName + "/constructedA"},
+		wiringErr.Cycle)
+}
+
+func TestSetCyclePolicyDefaultContainer(t *testing.T) {
+	SetCyclePolicy(RejectCycles)
+	Autowire(&cycleA{})
+	assert.True(t, errors.Is(TryAutowire(&cycleB{}), ErrCircularDependency))
+	SetCyclePolicy(AllowCycles)
+	assert.Equal(t, 0, len(Close()))
+}
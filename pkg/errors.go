@@ -0,0 +1,152 @@
+package pkg
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotPointer is reported when the value which should be autowired is not a struct pointer.
+	ErrNotPointer = errors.New("expected struct pointer")
+	// ErrInterfaceMismatch is reported when the found dependency doesn't implement the type of the field.
+	ErrInterfaceMismatch = errors.New("dependency doesn't implement field type")
+	// ErrNotFound is reported when the requested dependency is not registered.
+	ErrNotFound = errors.New("dependency not found")
+	// ErrAmbiguous is reported when more than one dependency matches the request.
+	ErrAmbiguous = errors.New("ambiguous dependency")
+	// ErrInvalidConstructor is reported when the constructor passed to Provide has unsupported signature.
+	ErrInvalidConstructor = errors.New("invalid constructor")
+	// ErrConstructorFailed is reported when the constructor returns an error or nil.
+	ErrConstructorFailed = errors.New("constructor failed")
+	// ErrInitFailed is reported when Init method of Initializer interface returns an error.
+	ErrInitFailed = errors.New("initialization failed")
+	// ErrStartFailed is reported when Start method of Starter interface returns an error.
+	ErrStartFailed = errors.New("start failed")
+	// ErrCloseFailed is reported when Close method of io.Closer interface returns an error.
+	ErrCloseFailed = errors.New("close failed")
+	// ErrShutdownTimeout is reported when the struct wasn't stopped before the deadline.
+	ErrShutdownTimeout = errors.New("shutdown timed out")
+	// ErrInvalidValue is reported when the configuration value couldn't be converted into the field type.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidConfig is reported when the configuration couldn't be read or parsed.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrOutOfScope is reported when dependency provided with Scoped lifetime is requested
+	// outside of the Scope, e.g. by singleton struct, or the Scope is already closed.
+	ErrOutOfScope = errors.New("dependency requested out of scope")
+	// ErrCircularDependency is reported when the dependencies form a cycle rejected by the CyclePolicy.
+	ErrCircularDependency = errors.New("circular dependency")
+)
+
+// WiringError represents failure occurred while wiring dependencies. The
+// cause of the failure is one of the Err* values, so WiringError could be
+// checked with errors.Is function, e.g. errors.Is(err, ErrNotFound), while
+// details could be retrieved with errors.As function.
+type WiringError struct {
+	// Err holds the cause of the failure.
+	Err error
+	// Struct holds full path of the struct being wired.
+	Struct string
+	// Field holds name of the struct field being wired.
+	Field string
+	// Tag holds value of the autowire tag of the field.
+	Tag string
+	// Dependency holds full path or type of the dependency, which caused the failure.
+	Dependency string
+	// Candidates holds full paths of all matching dependencies in case of ErrAmbiguous.
+	Candidates []string
+	// Cycle holds full paths of the dependencies forming a cycle in case of ErrCircularDependency.
+	Cycle []string
+	// Cause holds the error returned by the constructor, Init, Start or Close method or
+	// the parsing error in case of ErrConstructorFailed, ErrInitFailed, ErrStartFailed,
+	// ErrCloseFailed, ErrInvalidValue or ErrInvalidConfig.
+	Cause error
+}
+
+// Error returns description of the failure.
+func (e *WiringError) Error() string {
+	var b strings.Builder
+	b.WriteString("autowire: ")
+	b.WriteString(e.Err.Error())
+	if e.Struct != "" {
+		b.WriteString(", struct " + e.Struct)
+	}
+	if e.Field != "" {
+		b.WriteString(", field " + e.Field)
+	}
+	if e.Tag != "" {
+		b.WriteString(", tag \"" + e.Tag + "\"")
+	}
+	if e.Dependency != "" {
+		b.WriteString(", dependency " + e.Dependency)
+	}
+	if len(e.Candidates) > 0 {
+		b.WriteString(", candidates [" + strings.Join(e.Candidates, ", ") + "]")
+	}
+	if len(e.Cycle) > 0 {
+		b.WriteString(", cycle " + formatCycle(e.Cycle))
+	}
+	if e.Cause != nil {
+		b.WriteString(": " + e.Cause.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *WiringError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the Cause matches the target, so the errors returned by
+// constructors could be checked with errors.Is function as well.
+func (e *WiringError) Is(target error) bool {
+	return e.Cause != nil && errors.Is(e.Cause, target)
+}
+
+// As finds the first error in the Cause chain matching the target.
+func (e *WiringError) As(target interface{}) bool {
+	return e.Cause != nil && errors.As(e.Cause, target)
+}
+
+// aggregate represents failures reported together by ValidationError, ShutdownError
+// and RunError, so they're matched by errors.Is and errors.As functions the same way.
+type aggregate []error
+
+// wiringFailures function returns aggregate of the wiring errors.
+func wiringFailures(errs []*WiringError) aggregate {
+	failures := make(aggregate, len(errs))
+	for i, err := range errs {
+		failures[i] = err
+	}
+	return failures
+}
+
+// describe method returns the summary followed by description of each failure on separate line.
+func (a aggregate) describe(summary string) string {
+	var b strings.Builder
+	b.WriteString("autowire: " + strconv.Itoa(len(a)) + " " + summary)
+	for _, err := range a {
+		b.WriteString("\n\t" + err.Error())
+	}
+	return b.String()
+}
+
+// is method reports whether any of the failures matches the target.
+func (a aggregate) is(target error) bool {
+	for _, err := range a {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// as method finds the first failure matching the target.
+func (a aggregate) as(target interface{}) bool {
+	for _, err := range a {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWiringError(t *testing.T) {
+	err := &WiringError{Err: ErrNotFound, Struct: "app/Application", Field: "userSvc",
+		Tag: "service/UserService", Dependency: "service/UserService"}
+	assert.Equal(t, "autowire: dependency not found, struct app/Application, field userSvc, "+
+		"tag \"service/UserService\", dependency service/UserService", err.Error())
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrNotPointer))
+
+	wrapped := fmt.Errorf("startup: %w", err)
+	var wiringErr *WiringError
+	assert.True(t, errors.As(wrapped, &wiringErr))
+	assert.Equal(t, "userSvc", wiringErr.Field)
+}
+
+func TestTryAutowireNotPointer(t *testing.T) {
+	c := NewContainer()
+	err := c.TryAutowire(fake.Foo{})
+	assert.True(t, errors.Is(err, ErrNotPointer))
+	err = c.TryAutowire(nil)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+	name := "name"
+	err = c.TryAutowire(&name)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+}
+
+func TestTryAutowireInterfaceMismatch(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.TryAutowire(&fake.Foo{}))
+	err := c.TryAutowire(&fake.Bor{})
+	assert.True(t, errors.Is(err, ErrInterfaceMismatch))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/internal/fake/Bor", wiringErr.Struct)
+	assert.Equal(t, "Passer", wiringErr.Field)
+	assert.Equal(t, "fake/Foo", wiringErr.Tag)
+	assert.Nil(t, c.Autowired(fake.Bor{}))
+}
+
+func TestTryAutowired(t *testing.T) {
+	c := NewContainer()
+	_, err := c.TryAutowired(fake.Foo{})
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/internal/fake/Foo", wiringErr.Dependency)
+
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	result, err := c.TryAutowired(&fake.Foo{})
+	assert.Nil(t, err)
+	assert.Equal(t, foo, result)
+
+	_, err = c.TryAutowired(nil)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+	_, err = c.TryAutowired(42)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+}
+
+func TestTryAutowireDefaultContainer(t *testing.T) {
+	assert.True(t, errors.Is(TryAutowire(fake.Foo{}), ErrNotPointer))
+	_, err := TryAutowired(fake.Foo{})
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
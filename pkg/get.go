@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"log"
+	"reflect"
+)
+
+// Get function returns dependency of type T registered inside the default Container.
+// T could be either a struct pointer or an interface, in case of interface the single
+// registered implementation is returned. Second result reports whether the dependency
+// was found, so there is no need of type assertions:
+//  app, ok := Get[*app.Application]()
+// Get returns false as well, when more than one implementation of the interface is registered.
+func Get[T any]() (T, bool) {
+	return GetFrom[T](defaultContainer)
+}
+
+// MustGet function works like Get function, but panics when the dependency is not found.
+//  app := MustGet[*app.Application]()
+func MustGet[T any]() T {
+	return MustGetFrom[T](defaultContainer)
+}
+
+// GetFrom function works like Get function, but looks up the dependency inside the given Container.
+func GetFrom[T any](c *Container) (T, bool) {
+	result, err := getFrom[T](c)
+	return result, err == nil
+}
+
+// MustGetFrom function works like MustGet function, but looks up the dependency inside the given Container.
+func MustGetFrom[T any](c *Container) T {
+	result, err := getFrom[T](c)
+	if err != nil {
+		log.Panicln(err.Error())
+	}
+	return result
+}
+
+// resolver represents either Container or Scope.
+type resolver interface {
+	lookupType(t reflect.Type) (interface{}, error)
+}
+
+func getFrom[T any](r resolver) (T, error) {
+	var result T
+	dependency, err := r.lookupType(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return result, err
+	}
+	return dependency.(T), nil
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type otherPasser struct{}
+
+// Pass method
+func (otherPasser) Pass() {
+}
+
+func TestGetStructPtr(t *testing.T) {
+	c := NewContainer()
+	_, ok := GetFrom[*fake.Foo](c)
+	assert.False(t, ok)
+	foo := &fake.Foo{Name: "test"}
+	c.Autowire(foo)
+	result, ok := GetFrom[*fake.Foo](c)
+	assert.True(t, ok)
+	assert.Equal(t, foo, result)
+	assert.Equal(t, foo, MustGetFrom[*fake.Foo](c))
+}
+
+func TestGetInterface(t *testing.T) {
+	c := NewContainer()
+	_, ok := GetFrom[fake.Passer](c)
+	assert.False(t, ok)
+	foo := &fake.Foo{Name: "test"}
+	c.Autowire(foo)
+	result, ok := GetFrom[fake.Passer](c)
+	assert.True(t, ok)
+	assert.Equal(t, foo, result)
+
+	c.Autowire(&otherPasser{})
+	_, ok = GetFrom[fake.Passer](c)
+	assert.False(t, ok)
+	_, err := getFrom[fake.Passer](c)
+	assert.True(t, errors.Is(err, ErrAmbiguous))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, []string{packageName + "/internal/fake/Foo", packageName + "/otherPasser"}, wiringErr.Candidates)
+}
+
+func TestGetUnsupportedType(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{})
+	_, err := getFrom[fake.Foo](c)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+	_, err = getFrom[string](c)
+	assert.True(t, errors.Is(err, ErrNotPointer))
+}
+
+func TestMustGetPanics(t *testing.T) {
+	c := NewContainer()
+	assert.Panics(t, func() {
+		MustGetFrom[*fake.Foo](c)
+	})
+	assert.Panics(t, func() {
+		MustGet[fake.Passer]()
+	})
+}
+
+func TestGetDefaultContainer(t *testing.T) {
+	foo := &fake.Foo{}
+	Autowire(foo)
+	result, ok := Get[*fake.Foo]()
+	assert.True(t, ok)
+	assert.Equal(t, foo, result)
+	assert.Equal(t, foo, MustGet[fake.Passer]())
+	assert.Equal(t, 0, len(Close()))
+}
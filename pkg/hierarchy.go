@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"errors"
+	"reflect"
+)
+
+// NewChild function returns new child Container of the default Container. For
+// more information take a look at NewChild method.
+func NewChild() *Container {
+	return defaultContainer.NewChild()
+}
+
+// NewChild method returns new empty Container, which falls back to the Container
+// when the dependency couldn't be found inside the child. Structs registered inside
+// the child shadow the ones of the parent without changing it, so each module or
+// test could override a few dependencies, e.g. swap BankAccountService for a fake:
+//  child := pkg.NewChild()
+//  child.Autowire(&FakeBankAccountService{}, &UserService{})
+// The nearest Container wins, the parent is looked up only when none of the
+// dependencies inside the child matches the field, while the dependency registered
+// inside the child later on replaces the inherited one. Structs registered inside the
+// child aren't visible to the parent, while closing the child closes only structs
+// registered inside the child. Child inherits CyclePolicy and shutdown timeout.
+func (c *Container) NewChild() *Container {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	child := NewContainer()
+	child.parent = c
+	child.cyclePolicy = c.cyclePolicy
+	child.shutdownTimeout = c.shutdownTimeout
+	return child
+}
+
+// inherit method injects the dependency resolved inside the ancestors of the Container
+// into the field. Returning false when none of the ancestors holds the dependency.
+// The field keeps waiting for the depName, so the dependency registered inside the
+// Container later on overrides the inherited one. The caller is expected to hold the lock.
+func (c *Container) inherit(elem reflect.Value, i int, structType string, tag string, depName string) (bool, error) {
+	if c.parent == nil {
+		return false, nil
+	}
+	key, dependency, err := c.parent.resolveInherited(elem.Type().Field(i).Type, parseTag(tag))
+	switch {
+	case err == nil:
+		c.markStructUninitialized(structType, depName,
+			unresolvedField{field: elem.Type().Field(i).Name, tag: tag, optional: parseTag(tag).optional})
+		return true, c.inject(elem, i, structType, tag, key, dependency)
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// resolveInherited method returns the key and the dependency of type t matching the
+// tag options resolved inside the Container or its ancestors. The lock of the Container
+// shouldn't be held by the caller, while the locks of its descendants may be held.
+func (c *Container) resolveInherited(t reflect.Type, options tagOptions) (string, interface{}, error) {
+	var key string
+	var dependency interface{}
+	err := c.update(func() (err error) {
+		key, dependency, err = c.resolveOptions(t, options)
+		return err
+	})
+	if c.parent != nil && isMissing(err, t.String()) {
+		return c.parent.resolveInherited(t, options)
+	}
+	return key, dependency, err
+}
+
+// resolveOptions method returns the key and the dependency of type t matching the tag options.
+// The caller is expected to hold the lock.
+func (c *Container) resolveOptions(t reflect.Type, options tagOptions) (string, interface{}, error) {
+	var candidates []string
+	switch {
+	case options.name != "":
+		if key, ok := c.names[options.name]; ok {
+			candidates = []string{key}
+		}
+	case options.qualifier != "":
+		candidates = c.findDependency(options.qualifier)
+	case t.Kind() == reflect.Interface:
+		candidates = c.findImplementations(t)
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		path := getFullPath(t.Elem().PkgPath(), t.String())
+		if _, ok := c.dependencies[path]; ok {
+			candidates = []string{path}
+		} else if _, ok = c.providers[path]; ok {
+			candidates = []string{path}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", nil, &WiringError{Err: ErrNotFound, Dependency: t.String()}
+	case 1:
+		dependency, err := c.resolvePath(candidates[0])
+		if err != nil {
+			return "", nil, err
+		}
+		if !reflect.TypeOf(dependency).AssignableTo(t) {
+			return "", nil, &WiringError{Err: ErrInterfaceMismatch, Dependency: reflect.TypeOf(dependency).String()}
+		}
+		return candidates[0], dependency, nil
+	default:
+		return "", nil, &WiringError{Err: ErrAmbiguous, Dependency: t.String(), Candidates: candidates}
+	}
+}
+
+// canInherit method reports whether the dependency of type t could be resolved
+// inside the ancestors of the Container. The caller is expected to hold the lock.
+func (c *Container) canInherit(t reflect.Type) bool {
+	if c.parent == nil {
+		return false
+	}
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+	return c.parent.canResolve(t, false) == nil
+}
+
+// isMissing function reports whether the error means the dependency registered
+// under the path is missing, rather than any of its own dependencies.
+func isMissing(err error, path string) bool {
+	var wiringErr *WiringError
+	return errors.As(err, &wiringErr) && wiringErr.Err == ErrNotFound && wiringErr.Dependency == path
+}
@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePasser struct{}
+
+// Pass method
+func (fakePasser) Pass() {
+}
+
+type childService struct {
+	passer fake.Passer
+}
+
+func newChildService(passer fake.Passer) *childService {
+	return &childService{passer: passer}
+}
+
+func TestChildFallsBackToParent(t *testing.T) {
+	parent := NewContainer()
+	foo := &fake.Foo{}
+	parent.Autowire(foo)
+	parent.AutowireNamed("primary", &namedConfig{})
+	child := parent.NewChild()
+
+	baz := &fake.Baz{}
+	quy := &fake.Quy{}
+	child.Autowire(baz, quy)
+	assert.Same(t, foo, baz.MyFoo)
+	assert.Same(t, foo, quy.Passer)
+	assert.Same(t, foo, child.Autowired(fake.Foo{}))
+	assert.Same(t, foo, MustGetFrom[fake.Passer](child))
+	assert.NotNil(t, child.Autowired("primary"))
+
+	child.Provide(newChildService)
+	assert.Nil(t, child.Validate())
+	assert.Same(t, foo, MustGetFrom[*childService](child).passer)
+	assert.Nil(t, parent.Autowired(fake.Baz{}))
+	assert.Nil(t, parent.Autowired(childService{}))
+}
+
+func TestChildShadowsParent(t *testing.T) {
+	parent := NewContainer()
+	parent.Autowire(&fake.Foo{Name: "parent"})
+	parentQuy := &fake.Quy{}
+	parent.Autowire(parentQuy)
+
+	child := parent.NewChild()
+	override := &fake.Foo{Name: "child"}
+	baz := &fake.Baz{}
+	child.Autowire(override, baz)
+	assert.Same(t, override, baz.MyFoo)
+	assert.Same(t, override, child.Autowired(fake.Foo{}))
+	assert.Equal(t, "parent", parent.Autowired(fake.Foo{}).(*fake.Foo).Name)
+
+	other := parent.NewChild()
+	passer := &fakePasser{}
+	otherQuy := &fake.Quy{}
+	other.Autowire(passer, otherQuy)
+	assert.Same(t, passer, otherQuy.Passer)
+	assert.NotSame(t, passer, parentQuy.Passer)
+
+	assert.Equal(t, 0, len(child.Close()))
+	assert.Equal(t, 1, override.CloseCalls)
+	assert.Equal(t, 0, parent.Autowired(fake.Foo{}).(*fake.Foo).CloseCalls)
+	assert.NotNil(t, parent.Autowired(fake.Quy{}))
+}
+
+func TestChildOverridesAfterInheriting(t *testing.T) {
+	parent := NewContainer()
+	parent.Autowire(&fake.Foo{Name: "parent"})
+	child := parent.NewChild()
+	baz := &fake.Baz{}
+	quy := &fake.Quy{}
+	child.Autowire(baz, quy)
+	assert.Equal(t, "parent", baz.MyFoo.Name)
+
+	override := &fake.Foo{Name: "child"}
+	child.Autowire(override)
+	assert.Same(t, override, baz.MyFoo)
+	assert.Same(t, override, quy.Passer)
+	assert.Nil(t, child.Validate())
+	assert.Equal(t, "parent", parent.Autowired(fake.Foo{}).(*fake.Foo).Name)
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseConfig function reads YAML, JSON or TOML document and flattens it into
+// properties keyed by dot separated lowercase path, e.g. server.port. Lists are
+// represented as comma separated values.
+func ParseConfig(r io.Reader, format string) (map[string]string, error) {
+	document := map[string]interface{}{}
+	var err error
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		err = yaml.NewDecoder(r).Decode(&document)
+		if err == io.EOF {
+			err = nil
+		}
+	case "json":
+		err = json.NewDecoder(r).Decode(&document)
+	case "toml":
+		_, err = toml.NewDecoder(r).Decode(&document)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]string{}
+	flatten(properties, "", document)
+	return properties, nil
+}
+
+func flatten(properties map[string]string, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(properties, join(prefix, key), child)
+		}
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			flatten(properties, join(prefix, fmt.Sprint(key)), child)
+		}
+	case []interface{}:
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			elements = append(elements, format(element))
+		}
+		properties[prefix] = strings.Join(elements, ",")
+	case []map[string]interface{}:
+		for i, child := range v {
+			flatten(properties, join(prefix, strconv.Itoa(i)), child)
+		}
+	default:
+		properties[prefix] = format(v)
+	}
+}
+
+func join(prefix string, key string) string {
+	key = strings.ToLower(key)
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
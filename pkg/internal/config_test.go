@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	expected := map[string]string{"server.port": "8080", "server.readtimeout": "5s",
+		"server.hosts": "first,second", "debug": "true", "ratio": "0.5"}
+	documents := map[string]string{
+		"yaml": "server:\n  port: 8080\n  readTimeout: 5s\n  hosts: [first, second]\ndebug: true\nratio: 0.5\n",
+		"json": `{"server": {"port": 8080, "readTimeout": "5s", "hosts": ["first", "second"]}, "debug": true, "ratio": 0.5}`,
+		"toml": "debug = true\nratio = 0.5\n[server]\nport = 8080\nreadTimeout = \"5s\"\nhosts = [\"first\", \"second\"]\n",
+	}
+	for format, document := range documents {
+		properties, err := ParseConfig(strings.NewReader(document), format)
+		assert.Nil(t, err, format)
+		assert.Equal(t, expected, properties, format)
+	}
+	properties, err := ParseConfig(strings.NewReader(""), "yml")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(properties))
+
+	_, err = ParseConfig(strings.NewReader("{"), "json")
+	assert.NotNil(t, err)
+	_, err = ParseConfig(strings.NewReader(""), "ini")
+	assert.NotNil(t, err)
+}
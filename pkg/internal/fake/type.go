@@ -47,6 +47,16 @@ func (q *Qus) Passer() Passer {
 	return q.passer
 }
 
+// Quy represent named struct autowiring interface instance by type into exported field
+type Quy struct {
+	Passer Passer `autowire:""`
+}
+
+// Quv represent named struct autowiring interface instance by type into unexported field
+type Quv struct {
+	passer Passer `autowire:""` //nolint:structcheck,unused
+}
+
 // NotFoundTagDependency represents named struct
 type NotFoundTagDependency struct {
 	passer Passer `autowire:"fake/FooBaz"` //nolint:structcheck,unused
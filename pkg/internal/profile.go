@@ -1,8 +1,12 @@
 package internal
 
 import (
+	"fmt"
+	"log"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -16,12 +20,62 @@ const (
 	Testing
 )
 
+const (
+	// ProfileEnv represents environment variable holding the profile, either production or testing.
+	ProfileEnv = "AUTOWIRE_PROFILE"
+	// ProgramNameEnv represents environment variable enabling detection of the profile from the
+	// program name, where any program containing test or Test is treated as Testing.
+	ProgramNameEnv = "AUTOWIRE_PROFILE_PROGRAM_NAME"
+)
+
+// String method returns the name of the profile.
+func (p Profile) String() string {
+	if p == Testing {
+		return "testing"
+	}
+	return "production"
+}
+
+// ParseProfile function returns profile of the given name, either production or testing.
+func ParseProfile(name string) (Profile, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case Production.String():
+		return Production, nil
+	case Testing.String():
+		return Testing, nil
+	}
+	return Production, fmt.Errorf("unknown profile %q", name)
+}
+
 // GetProfile function returns current profile
 func GetProfile() Profile {
-	args := os.Args
-	programName := args[0][strings.LastIndex(args[0], "/"):]
-	if result, _ := regexp.MatchString("/.*[Tt]est", programName); result {
-		return Testing
+	profile, _ := DetectProfile()
+	return profile
+}
+
+// DetectProfile function returns current profile together with the source of the
+// decision. The profile is taken from AUTOWIRE_PROFILE environment variable, then
+// from testing.Testing() when supported by the Go version, then from the program
+// name when enabled by AUTOWIRE_PROFILE_PROGRAM_NAME. Otherwise Production is returned.
+// Invalid value of AUTOWIRE_PROFILE environment variable is logged and ignored.
+func DetectProfile() (Profile, string) {
+	if name, ok := os.LookupEnv(ProfileEnv); ok {
+		profile, err := ParseProfile(name)
+		if err == nil {
+			return profile, "environment variable " + ProfileEnv
+		}
+		log.Printf("Ignoring environment variable %s: %s", ProfileEnv, err)
+	}
+	if testing, ok := isTesting(); ok && testing {
+		return Testing, "testing.Testing()"
 	}
-	return Production
+	if enabled, _ := strconv.ParseBool(os.Getenv(ProgramNameEnv)); enabled && isTestProgram(os.Args[0]) {
+		return Testing, "program name"
+	}
+	return Production, "default"
+}
+
+func isTestProgram(program string) bool {
+	result, _ := regexp.MatchString("[Tt]est", filepath.Base(program))
+	return result
 }
@@ -1,7 +1,12 @@
 package internal
 
 import (
+	"bytes"
+	"log"
+	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_Profile(t *testing.T) {
@@ -10,3 +15,33 @@ func Test_Profile(t *testing.T) {
 		t.Errorf("Expected Profile testing found active")
 	}
 }
+
+func TestDetectProfile(t *testing.T) {
+	t.Setenv(ProfileEnv, "Production")
+	profile, source := DetectProfile()
+	assert.Equal(t, Production, profile)
+	assert.Equal(t, "environment variable "+ProfileEnv, source)
+
+	t.Setenv(ProfileEnv, "unknown")
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+	profile, source = DetectProfile()
+	assert.Equal(t, Testing, profile)
+	assert.Equal(t, "testing.Testing()", source)
+	assert.Contains(t, output.String(), `unknown profile "unknown"`)
+}
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile("testing")
+	assert.Nil(t, err)
+	assert.Equal(t, Testing, profile)
+	_, err = ParseProfile("staging")
+	assert.NotNil(t, err)
+}
+
+func Test_isTestProgram(t *testing.T) {
+	assert.True(t, isTestProgram("/tmp/go-build/pkg.test"))
+	assert.True(t, isTestProgram("integrationTest"))
+	assert.False(t, isTestProgram("/usr/bin/service"))
+}
@@ -31,3 +31,11 @@ func SetFieldValue(elem reflect.Value, i int, dependency interface{}) {
 		SetUnexportedField(elem.Field(i), dependency)
 	}
 }
+
+// FieldAddr functions returns pointer to the field.
+// It supports exported and unexported fields.
+func FieldAddr(elem reflect.Value, i int) interface{} {
+	field := elem.Field(i)
+	//nolint:gosec
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Interface()
+}
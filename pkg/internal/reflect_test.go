@@ -30,3 +30,9 @@ func TestSetFieldValue(t *testing.T) {
 	SetFieldValue(reflect.ValueOf(foo).Elem(), 0, "changed")
 	assert.Equal(t, foo.unexported, "changed")
 }
+
+func TestFieldAddr(t *testing.T) {
+	foo := &Foo{unexported: "old"}
+	*FieldAddr(reflect.ValueOf(foo).Elem(), 0).(*string) = "changed"
+	assert.Equal(t, foo.unexported, "changed")
+}
@@ -0,0 +1,11 @@
+//go:build go1.21
+
+package internal
+
+import "testing"
+
+// isTesting function reports whether the program is a test binary. Second result
+// reports whether the detection is supported by the Go version.
+func isTesting() (bool, bool) {
+	return testing.Testing(), true
+}
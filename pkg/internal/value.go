@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnterminatedPlaceholder is returned when placeholder isn't closed with the brace.
+var ErrUnterminatedPlaceholder = errors.New("unterminated placeholder")
+
+// ErrUnsupportedType is returned when the value couldn't be converted into the field type.
+var ErrUnsupportedType = errors.New("unsupported field type")
+
+// MissingKeyError is returned when placeholder without default value refers to missing key.
+type MissingKeyError struct {
+	Key string
+}
+
+func (e *MissingKeyError) Error() string {
+	return "missing value of " + e.Key
+}
+
+// durationType represents time.Duration, which is parsed from its string representation.
+//nolint:gochecknoglobals
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ResolvePlaceholders function replaces each ${KEY:default} placeholder inside the
+// expression with the value returned by the lookup function or the default value.
+// Placeholder without default value, e.g. ${KEY}, requires the key to be present.
+func ResolvePlaceholders(expression string, lookup func(key string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(expression, "${")
+		if start < 0 {
+			b.WriteString(expression)
+			return b.String(), nil
+		}
+		end := strings.Index(expression[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("%w in %q", ErrUnterminatedPlaceholder, expression)
+		}
+		b.WriteString(expression[:start])
+		placeholder := expression[start+2 : start+end]
+		key, defaultValue, hasDefault := strings.Cut(placeholder, ":")
+		key = strings.TrimSpace(key)
+		if value, ok := lookup(key); ok {
+			b.WriteString(value)
+		} else if hasDefault {
+			b.WriteString(defaultValue)
+		} else {
+			return "", &MissingKeyError{Key: key}
+		}
+		expression = expression[start+end+1:]
+	}
+}
+
+// IsNested function reports whether the value of type t holds nested properties
+// instead of being converted from a single value by ConvertValue function.
+func IsNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct
+}
+
+// ConvertValue function converts the raw value into the value of type t. Supported
+// types are string, bool, integers, floats, time.Duration and slices of them,
+// which are represented as comma separated list.
+func ConvertValue(raw string, t reflect.Type) (reflect.Value, error) {
+	result := reflect.New(t).Elem()
+	if t == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return result, err
+		}
+		result.SetInt(int64(duration))
+		return result, nil
+	}
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		result.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return result, err
+		}
+		result.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetFloat(value)
+	case reflect.Slice:
+		if strings.TrimSpace(raw) == "" {
+			return reflect.MakeSlice(t, 0, 0), nil
+		}
+		elements := strings.Split(raw, ",")
+		result = reflect.MakeSlice(t, 0, len(elements))
+		for _, element := range elements {
+			value, err := ConvertValue(strings.TrimSpace(element), t.Elem())
+			if err != nil {
+				return result, err
+			}
+			result = reflect.Append(result, value)
+		}
+	default:
+		return result, fmt.Errorf("%w %s", ErrUnsupportedType, t)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookup(key string) (string, bool) {
+	value, ok := map[string]string{"HOST": "example.com", "EMPTY": ""}[key]
+	return value, ok
+}
+
+func TestResolvePlaceholders(t *testing.T) {
+	value, err := ResolvePlaceholders("http://${HOST:localhost}:${PORT:8080}/", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://example.com:8080/", value)
+	value, err = ResolvePlaceholders("${EMPTY:default}${URL:http://localhost}", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost", value)
+	value, err = ResolvePlaceholders("literal", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "literal", value)
+
+	_, err = ResolvePlaceholders("${API_KEY}", lookup)
+	var missing *MissingKeyError
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, "API_KEY", missing.Key)
+	_, err = ResolvePlaceholders("${HOST", lookup)
+	assert.True(t, errors.Is(err, ErrUnterminatedPlaceholder))
+}
+
+func TestConvertValue(t *testing.T) {
+	value, err := ConvertValue("42", reflect.TypeOf(int32(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(42), value.Interface())
+	value, err = ConvertValue("1m30s", reflect.TypeOf(time.Duration(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, value.Interface())
+	value, err = ConvertValue("true", reflect.TypeOf(false))
+	assert.Nil(t, err)
+	assert.Equal(t, true, value.Interface())
+	value, err = ConvertValue("a, b,c", reflect.TypeOf([]string{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, value.Interface())
+	value, err = ConvertValue("", reflect.TypeOf([]int{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, value.Interface())
+	value, err = ConvertValue("0.5", reflect.TypeOf(float64(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, value.Interface())
+
+	_, err = ConvertValue("300", reflect.TypeOf(uint8(0)))
+	assert.NotNil(t, err)
+	_, err = ConvertValue("x", reflect.TypeOf(0))
+	assert.NotNil(t, err)
+	_, err = ConvertValue("x", reflect.TypeOf(struct{}{}))
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+}
+
+func TestIsNested(t *testing.T) {
+	assert.True(t, IsNested(reflect.TypeOf(struct{ Port int }{})))
+	assert.False(t, IsNested(reflect.TypeOf(time.Second)))
+	assert.False(t, IsNested(reflect.TypeOf([]string{})))
+}
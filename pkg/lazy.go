@@ -0,0 +1,149 @@
+package pkg
+
+import (
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+// binder represents field, which is bound to the Container instead of being injected.
+type binder interface {
+	bind(c *Container, options tagOptions)
+}
+
+//nolint:gochecknoglobals
+var binderType = reflect.TypeOf((*binder)(nil)).Elem()
+
+// lazyState holds the Container and the Scope the field is bound to, shared by the copies of the field.
+type lazyState struct {
+	container *Container
+	scope     *Scope
+	options   tagOptions
+	mu        sync.Mutex
+	resolved  bool
+	value     interface{}
+}
+
+// Lazy represents field holding dependency of type T, which is resolved and constructed
+// on the first use instead of when the struct is registered, e.g. expensive connection
+// pools used only on rare code paths. T could be either a struct pointer or an interface,
+// while the autowire tag options like qualifier or name are respected:
+//  type ReportService struct {
+//      client pkg.Lazy[*ExpensiveClient] `autowire:""`
+//  }
+//  func (s *ReportService) Report() {
+//      s.client.Get().Send()
+//  }
+// Lazy fields don't form dependencies of the struct, so they break circular
+// dependencies under any CyclePolicy, unlike lazy tag option, which only permits
+// the eagerly injected field to close the cycle under AllowLazyCycles policy.
+// Lazy fields are never reported by Validate function. Lazy is safe for concurrent
+// use by multiple goroutines. Get method shouldn't be invoked inside the constructor
+// passed to Provide function, as the Container is locked during the construction.
+// Lazy fields of structs wired inside the Scope are resolved inside that Scope, so
+// they could hold dependencies provided with Scoped lifetime until the Scope is closed.
+type Lazy[T any] struct {
+	state *lazyState
+}
+
+// Get method returns the dependency, resolving it on the first use. Get panics when
+// the dependency couldn't be resolved.
+func (l Lazy[T]) Get() T {
+	dependency, err := l.TryGet()
+	if err != nil {
+		log.Panicln(err.Error())
+	}
+	return dependency
+}
+
+// TryGet method works like Get method, but instead of panicking returns *WiringError
+// describing the failure. Failed resolution is retried on the next use.
+func (l Lazy[T]) TryGet() (T, error) {
+	var result T
+	if l.state == nil {
+		return result, &WiringError{Err: ErrNotFound, Dependency: reflect.TypeOf((*T)(nil)).Elem().String()}
+	}
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+	if !l.state.resolved {
+		dependency, err := resolveBound[T](l.state)
+		if err != nil {
+			return result, err
+		}
+		l.state.value = dependency
+		l.state.resolved = true
+	}
+	return l.state.value.(T), nil
+}
+
+func (l *Lazy[T]) bind(c *Container, options tagOptions) {
+	if l.state == nil {
+		l.state = &lazyState{container: c, scope: c.scope, options: options}
+	}
+}
+
+// Provider represents field resolving dependency of type T on each use, so dependencies
+// provided with Prototype lifetime are constructed once again each time:
+//  type ReportService struct {
+//      builders pkg.Provider[*ReportBuilder] `autowire:""`
+//  }
+// For more information take a look at Lazy type.
+type Provider[T any] struct {
+	state *lazyState
+}
+
+// Get method resolves the dependency. Get panics when the dependency couldn't be resolved.
+func (p Provider[T]) Get() T {
+	dependency, err := p.TryGet()
+	if err != nil {
+		log.Panicln(err.Error())
+	}
+	return dependency
+}
+
+// TryGet method works like Get method, but instead of panicking returns *WiringError
+// describing the failure.
+func (p Provider[T]) TryGet() (T, error) {
+	if p.state == nil {
+		var result T
+		return result, &WiringError{Err: ErrNotFound, Dependency: reflect.TypeOf((*T)(nil)).Elem().String()}
+	}
+	return resolveBound[T](p.state)
+}
+
+func (p *Provider[T]) bind(c *Container, options tagOptions) {
+	if p.state == nil {
+		p.state = &lazyState{container: c, scope: c.scope, options: options}
+	}
+}
+
+// resolveBound function resolves the dependency of type T inside the Scope or the Container
+// the field is bound to.
+func resolveBound[T any](state *lazyState) (T, error) {
+	var result T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	var dependency interface{}
+	var err error
+	if state.scope != nil {
+		dependency, err = state.scope.resolve(t, state.options)
+	} else {
+		_, dependency, err = state.container.resolveInherited(t, state.options)
+	}
+	if err != nil {
+		return result, err
+	}
+	return dependency.(T), nil
+}
+
+// bindField method binds the field to the Container and the current Scope, in case the field
+// is Lazy or Provider.
+// Returning false, when the field should be injected.
+func (c *Container) bindField(elem reflect.Value, i int, options tagOptions) bool {
+	if !reflect.PtrTo(elem.Type().Field(i).Type).Implements(binderType) {
+		return false
+	}
+	internal.FieldAddr(elem, i).(binder).bind(c, options)
+	return true
+}
@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type expensiveClient struct {
+	Foo *fake.Foo `autowire:""`
+}
+
+type lazyService struct {
+	client   Lazy[*expensiveClient] `autowire:""`
+	passer   Lazy[fake.Passer]      `autowire:"fake/Foo"`
+	builders Provider[*reportBuilder]
+	Builders Provider[*reportBuilder] `autowire:""`
+}
+
+type deferredCycleA struct {
+	b Lazy[*deferredCycleB] `autowire:""`
+}
+
+type deferredCycleB struct {
+	a *deferredCycleA `autowire:""`
+}
+
+func TestLazy(t *testing.T) {
+	c := NewContainer()
+	constructed := 0
+	c.Provide(func(foo *fake.Foo) *expensiveClient {
+		constructed++
+		return &expensiveClient{}
+	})
+	c.Provide(newReportBuilder, Prototype)
+	service := &lazyService{}
+	c.Autowire(service)
+	assert.Equal(t, 0, constructed)
+
+	_, err := service.client.TryGet()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	c.Autowire(&fake.Foo{})
+	assert.Nil(t, c.Validate())
+	assert.Equal(t, 0, constructed)
+	var wg sync.WaitGroup
+	clients := make([]*expensiveClient, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i] = service.client.Get()
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 1, constructed)
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+	assert.Same(t, c.Autowired(fake.Foo{}), clients[0].Foo)
+	assert.Same(t, c.Autowired(fake.Foo{}), service.passer.Get())
+
+	assert.NotSame(t, service.Builders.Get(), service.Builders.Get())
+	_, err = service.builders.TryGet()
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestLazyBreaksCycle(t *testing.T) {
+	c := NewContainer()
+	c.SetCyclePolicy(RejectCycles)
+	a := &deferredCycleA{}
+	b := &deferredCycleB{}
+	assert.Nil(t, c.TryAutowire(a, b))
+	assert.Same(t, a, b.a)
+	assert.Same(t, b, a.b.Get())
+}
+
+type lazyRequestService struct {
+	tx    Lazy[*transaction]     `autowire:""`
+	users Provider[*currentUser] `autowire:""`
+}
+
+func TestLazyInsideScope(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{})
+	c.Provide(newTransaction, Scoped)
+	c.Provide(func() *lazyRequestService { return &lazyRequestService{} }, Scoped)
+	s := c.NewScope(context.Background())
+	s.Autowire(&currentUser{id: "first"})
+	service := MustGetFromScope[*lazyRequestService](s)
+	assert.Same(t, MustGetFromScope[*transaction](s), service.tx.Get())
+	assert.Equal(t, "first", service.users.Get().id)
+	assert.Equal(t, 0, len(s.Close()))
+	_, err := service.users.TryGet()
+	assert.True(t, errors.Is(err, ErrOutOfScope))
+}
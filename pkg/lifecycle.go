@@ -0,0 +1,192 @@
+package pkg
+
+import (
+	"io"
+	"log"
+	"sort"
+)
+
+// Initializer interface could be implemented by the autowired structs, which
+// need to be notified once all their dependencies are injected. Init method is
+// invoked exactly once, after all the required tagged fields of the struct are
+// injected and all its dependencies are initialized, so the structs are initialized
+// in dependency order. This way lazy sync.Once guards are no longer needed:
+//  func (s *UserService) Init() error {
+//      return s.repository.Migrate()
+//  }
+// Error returned by Init method is reported as *WiringError caused by ErrInitFailed
+// by Validate, Finalize and Run functions, while the registration, which completed
+// the wiring (e.g. Autowire), doesn't fail, as it may be unrelated to the failed struct.
+type Initializer interface {
+	Init() error
+}
+
+// initState represents initialization state of the registered dependency.
+type initState int
+
+const (
+	initPending initState = iota
+	initRunning
+	initDone
+	initFailed
+)
+
+// initialize method invokes Init method of all the dependencies, which became
+// ready to be initialized. The lock shouldn't be held by the caller, as Init
+// methods are invoked without holding it. Failures are recorded, so they're
+// reported by Validate, Finalize and Run functions.
+func (c *Container) initialize() {
+	for {
+		c.mu.Lock()
+		ready := c.readyToInitialize()
+		c.mu.Unlock()
+		if len(ready) == 0 {
+			return
+		}
+		for _, key := range ready {
+			state := initDone
+			if err := c.initializeDependency(key); err != nil {
+				state = initFailed
+			}
+			c.mu.Lock()
+			c.initialized[key] = state
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *Container) initializeDependency(key string) error {
+	c.mu.RLock()
+	dependency := c.dependencies[key]
+	c.mu.RUnlock()
+	initializer, ok := dependency.(Initializer)
+	if !ok {
+		return nil
+	}
+	log.Printf("Initializing %s", key)
+	if err := initializer.Init(); err != nil {
+		wiringErr := &WiringError{Err: ErrInitFailed, Struct: key, Cause: err}
+		c.mu.Lock()
+		c.initErrors = append(c.initErrors, wiringErr)
+		c.mu.Unlock()
+		return wiringErr
+	}
+	return nil
+}
+
+// readyToInitialize method returns keys of the dependencies sorted by
+// registration order, whose required fields are injected and all their
+// dependencies are initialized, marking them as running.
+// The caller is expected to hold the lock.
+func (c *Container) readyToInitialize() []string {
+	blocked := map[string]bool{}
+	for _, depMap := range c.requiredDependencies {
+		for structType, fields := range depMap {
+			dependency, ok := c.dependencies[structType]
+			if !ok {
+				continue
+			}
+			for _, field := range fields {
+				if !field.optional && isFieldZero(dependency, field.field) {
+					blocked[structType] = true
+				}
+			}
+		}
+	}
+	var ready []string
+	for key := range c.dependencies {
+		if c.initialized[key] == initPending && !blocked[key] && c.dependenciesInitialized(key) {
+			ready = append(ready, key)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		return c.sequence[ready[i]] < c.sequence[ready[j]]
+	})
+	for _, key := range ready {
+		c.initialized[key] = initRunning
+	}
+	return ready
+}
+
+// dependenciesInitialized method reports whether all the dependencies of the
+// key are initialized. Dependencies forming a cycle with the key are skipped.
+func (c *Container) dependenciesInitialized(key string) bool {
+	for to := range c.edges[key] {
+		if to == key || c.initialized[to] == initDone {
+			continue
+		}
+		if c.findPath(to, key, map[string]bool{}) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyLevels method groups the keys of registered dependencies into levels
+// according to the dependency graph. First level holds dependencies, which don't
+// depend on any other, while each next level holds dependencies depending only on
+// the previous levels. Dependencies forming a cycle are grouped into the last level.
+// Each level is sorted by registration order. The caller is expected to hold the lock.
+func (c *Container) dependencyLevels() [][]string {
+	remaining := make(map[string]bool, len(c.dependencies))
+	for key := range c.dependencies {
+		remaining[key] = true
+	}
+	var levels [][]string
+	for len(remaining) > 0 {
+		var level []string
+		for key := range remaining {
+			if !c.dependsOnAny(key, remaining) {
+				level = append(level, key)
+			}
+		}
+		if len(level) == 0 {
+			for key := range remaining {
+				level = append(level, key)
+			}
+		}
+		sort.Slice(level, func(i, j int) bool {
+			return c.sequence[level[i]] < c.sequence[level[j]]
+		})
+		for _, key := range level {
+			delete(remaining, key)
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+// dependsOnAny method reports whether the dependency registered under the key depends on any of the keys.
+func (c *Container) dependsOnAny(key string, keys map[string]bool) bool {
+	for to := range c.edges[key] {
+		if to != key && keys[to] {
+			return true
+		}
+	}
+	return false
+}
+
+// closeDependency function invokes Close method in case the dependency implements
+// io.Closer interface and reports *WiringError caused by ErrCloseFailed.
+func closeDependency(key string, dependency interface{}) *WiringError {
+	closer, ok := dependency.(io.Closer)
+	if !ok {
+		return nil
+	}
+	log.Printf("Closing %s", key)
+	if err := closer.Close(); err != nil {
+		log.Println(err.Error())
+		return &WiringError{Err: ErrCloseFailed, Struct: key, Cause: err}
+	}
+	return nil
+}
+
+// initFailure method returns *ValidationError listing the failed Init methods, if any.
+func (c *Container) initFailure() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.initErrors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: append([]*WiringError{}, c.initErrors...)}
+}
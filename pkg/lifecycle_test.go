@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+var errInit = errors.New("init error")
+
+type initRecorder struct {
+	calls []string
+}
+
+type initRepository struct {
+	recorder *initRecorder
+	err      error
+}
+
+func (r *initRepository) Init() error {
+	r.recorder.calls = append(r.recorder.calls, "repository")
+	return r.err
+}
+
+type initService struct {
+	recorder   *initRecorder
+	repository *initRepository `autowire:""`
+	Passer     fake.Passer     `autowire:",optional"`
+}
+
+func (s *initService) Init() error {
+	s.recorder.calls = append(s.recorder.calls, "service")
+	return nil
+}
+
+type initApplication struct {
+	recorder *initRecorder
+	service  *initService `autowire:""`
+	plugins  []plugin     `autowire:""`
+}
+
+func (a *initApplication) Init() error {
+	a.recorder.calls = append(a.recorder.calls, "application")
+	return nil
+}
+
+func TestInitializerDependencyOrder(t *testing.T) {
+	c := NewContainer()
+	recorder := &initRecorder{}
+	c.Autowire(&initApplication{recorder: recorder}, &initService{recorder: recorder})
+	assert.Equal(t, 0, len(recorder.calls))
+	c.Autowire(&initRepository{recorder: recorder})
+	assert.Equal(t, []string{"repository", "service", "application"}, recorder.calls)
+
+	c.Autowire(&firstPlugin{}, &fake.Foo{})
+	assert.Equal(t, []string{"repository", "service", "application"}, recorder.calls)
+	assert.Nil(t, c.Validate())
+}
+
+func TestInitializerProvided(t *testing.T) {
+	c := NewContainer()
+	recorder := &initRecorder{}
+	c.Provide(func() *initRepository { return &initRepository{recorder: recorder} })
+	c.Autowire(&initService{recorder: recorder})
+	assert.Equal(t, []string{"repository", "service"}, recorder.calls)
+}
+
+func TestInitializerError(t *testing.T) {
+	c := NewContainer()
+	recorder := &initRecorder{}
+	c.Autowire(&initService{recorder: recorder})
+	assert.Nil(t, c.TryAutowire(&initRepository{recorder: recorder, err: errInit}))
+	assert.Equal(t, []string{"repository"}, recorder.calls)
+	err := c.Finalize()
+	assert.True(t, errors.Is(err, ErrInitFailed))
+	assert.True(t, errors.Is(err, errInit))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/initRepository", wiringErr.Struct)
+	assert.True(t, errors.Is(c.Run(context.Background()), ErrInitFailed))
+
+	err = c.Validate()
+	assert.True(t, errors.Is(err, ErrInitFailed))
+	assert.True(t, errors.Is(err, errInit))
+	assert.Equal(t, 0, len(c.Close()))
+	assert.Nil(t, c.Validate())
+}
+
+func TestInitializerErrorDoesntPanicInAutowire(t *testing.T) {
+	c := NewContainer()
+	recorder := &initRecorder{}
+	c.Autowire(&initService{recorder: recorder})
+	c.Provide(func() *initRepository { return &initRepository{recorder: recorder, err: errInit} })
+	assert.NotPanics(t, func() {
+		c.Autowire(&fake.Foo{})
+	})
+	assert.True(t, errors.Is(c.Validate(), ErrInitFailed))
+}
+
+var errClose = errors.New("close error")
+
+type closeRecorder struct {
+	calls []string
+}
+
+type closeRepository struct {
+	recorder *closeRecorder
+	err      error
+}
+
+func (r *closeRepository) Close() error {
+	r.recorder.calls = append(r.recorder.calls, "repository")
+	return r.err
+}
+
+type closeService struct {
+	recorder   *closeRecorder
+	repository *closeRepository `autowire:""`
+}
+
+func (s *closeService) Close() error {
+	s.recorder.calls = append(s.recorder.calls, "service")
+	return nil
+}
+
+type closeApplication struct {
+	recorder *closeRecorder
+	service  *closeService `autowire:""`
+}
+
+func (a *closeApplication) Close() error {
+	a.recorder.calls = append(a.recorder.calls, "application")
+	return nil
+}
+
+func TestCloseReverseDependencyOrder(t *testing.T) {
+	c := NewContainer()
+	recorder := &closeRecorder{}
+	c.Autowire(&closeRepository{recorder: recorder}, &closeService{recorder: recorder},
+		&closeApplication{recorder: recorder})
+	assert.Equal(t, 0, len(c.Close()))
+	assert.Equal(t, []string{"application", "service", "repository"}, recorder.calls)
+
+	recorder.calls = nil
+	c.Autowire(&closeApplication{recorder: recorder}, &closeService{recorder: recorder},
+		&closeRepository{recorder: recorder})
+	assert.Equal(t, 0, len(c.Close()))
+	assert.Equal(t, []string{"application", "service", "repository"}, recorder.calls)
+}
+
+func TestCloseError(t *testing.T) {
+	c := NewContainer()
+	recorder := &closeRecorder{}
+	c.Autowire(&closeRepository{recorder: recorder, err: errClose}, &closeService{recorder: recorder})
+	errs := c.Close()
+	assert.Equal(t, 1, len(errs))
+	assert.True(t, errors.Is(errs[0], ErrCloseFailed))
+	assert.True(t, errors.Is(errs[0], errClose))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(errs[0], &wiringErr))
+	assert.Equal(t, packageName+"/closeRepository", wiringErr.Struct)
+	assert.Equal(t, []string{"service", "repository"}, recorder.calls)
+}
+
+func TestDependencyLevelsCycle(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&cycleA{}, &cycleB{})
+	levels := c.dependencyLevels()
+	assert.Equal(t, 1, len(levels))
+	assert.Equal(t, 2, len(levels[0]))
+}
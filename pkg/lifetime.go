@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"log"
+	"reflect"
+)
+
+// newOption represents autowire tag option requesting fresh instance of the dependency.
+const newOption = "new"
+
+// Option represents option of the registration passed to Provide or Register function,
+// e.g. Prototype lifetime or Condition.
+type Option interface {
+	apply(r *registration)
+}
+
+// registration holds the options of the registration.
+type registration struct {
+	lifetime   Lifetime
+	conditions []Condition
+}
+
+// newRegistration function applies the options on the default registration.
+func newRegistration(options []Option) registration {
+	var r registration
+	for _, option := range options {
+		option.apply(&r)
+	}
+	return r
+}
+
+// Lifetime represents how long the dependency constructed by the provider lives.
+type Lifetime int
+
+const (
+	// Singleton lifetime means the dependency is constructed once and shared by all the
+	// structs depending on it. Singleton is the default lifetime.
+	Singleton Lifetime = iota
+	// Prototype lifetime means fresh instance is constructed and wired each time the
+	// dependency is injected or looked up, e.g. buffers or per-operation builders:
+	//  pkg.Provide(NewReportBuilder, pkg.Prototype)
+	// Prototype instances are wired with the dependencies registered at the time of the
+	// injection. They are not registered inside the Container, so neither their Init,
+	// Start, Stop nor Close methods are invoked.
+	Prototype
+	// Scoped lifetime means the dependency is constructed once per Scope, e.g. per
+	// http request, and closed when the Scope is closed:
+	//  pkg.Provide(NewTransaction, pkg.Scoped)
+	// Scoped dependency could be injected only into other Scoped or Prototype
+	// dependencies resolved inside the Scope, as injecting it into singleton would
+	// leak it out of the Scope, which is reported as *WiringError caused by ErrOutOfScope.
+	// Constructor of Scoped dependency may accept context.Context argument, which
+	// is resolved as the context of the Scope.
+	Scoped
+)
+
+func (l Lifetime) apply(r *registration) {
+	r.lifetime = l
+}
+
+// isPrototype method reports whether the dependency registered under the path has Prototype lifetime.
+func (c *Container) isPrototype(path string) bool {
+	p, ok := c.providers[path]
+	return ok && p.lifetime == Prototype
+}
+
+// newInstance method constructs fresh instance of the dependency registered under
+// the path and wires its dependencies without registering it. Instance is constructed
+// by the provider registered under the path, otherwise zero value of the struct
+// pointer type t is used. The caller is expected to hold the lock.
+func (c *Container) newInstance(path string, t reflect.Type) (interface{}, error) {
+	if err := c.pushConstructing(path); err != nil {
+		return nil, err
+	}
+	defer c.popConstructing()
+	var instance interface{}
+	if p, ok := c.constructors[path]; ok {
+		var err error
+		if instance, err = c.construct(p); err != nil {
+			return nil, err
+		}
+	} else {
+		if dependency, ok := c.dependencies[path]; ok {
+			t = reflect.TypeOf(dependency)
+		}
+		if t == nil {
+			return nil, &WiringError{Err: ErrNotFound, Dependency: path}
+		}
+		instance = reflect.New(t.Elem()).Interface()
+	}
+	log.Printf("Autowiring new instance of %s", path)
+	if err := c.autowireDependencies(reflect.ValueOf(instance), path); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// pushConstructing method marks the path as being constructed, unless it's already
+// being constructed, which means the dependencies form a cycle.
+func (c *Container) pushConstructing(path string) error {
+	for i, constructing := range c.constructing {
+		if constructing == path {
+			cycle := append(append([]string{}, c.constructing[i:]...), path)
+			return &WiringError{Err: ErrCircularDependency, Struct: path, Cycle: cycle}
+		}
+	}
+	c.constructing = append(c.constructing, path)
+	return nil
+}
+
+// popConstructing method removes the last path being constructed.
+func (c *Container) popConstructing() {
+	c.constructing = c.constructing[:len(c.constructing)-1]
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type reportBuilder struct {
+	Foo   *fake.Foo `autowire:""`
+	lines []string
+}
+
+func newReportBuilder() *reportBuilder {
+	return &reportBuilder{lines: []string{"header"}}
+}
+
+type reportService struct {
+	builder *reportBuilder `autowire:""`
+}
+
+type otherReportService struct {
+	builder *reportBuilder `autowire:""`
+}
+
+type freshReportService struct {
+	builder *reportBuilder `autowire:",new"`
+	buffer  *fake.Foo      `autowire:",new"`
+}
+
+type selfPrototype struct {
+	next *selfPrototype `autowire:",new"`
+}
+
+func TestPrototypeProvide(t *testing.T) {
+	c := NewContainer()
+	c.Provide(newReportBuilder, Prototype)
+	foo := &fake.Foo{}
+	first := &reportService{}
+	second := &otherReportService{}
+	c.Autowire(foo, first, second)
+	assert.NotNil(t, first.builder)
+	assert.NotNil(t, second.builder)
+	assert.NotSame(t, first.builder, second.builder)
+	assert.Equal(t, []string{"header"}, first.builder.lines)
+	assert.Equal(t, foo, first.builder.Foo)
+
+	fromGet := MustGetFrom[*reportBuilder](c)
+	assert.NotSame(t, first.builder, fromGet)
+	assert.NotSame(t, fromGet, MustGetFrom[*reportBuilder](c))
+	assert.Nil(t, c.dependencies[packageName+"/reportBuilder"])
+	assert.Nil(t, c.Validate())
+}
+
+func TestPrototypeProvidedAfterRegistration(t *testing.T) {
+	c := NewContainer()
+	first := &reportService{}
+	c.Autowire(first, &fake.Foo{})
+	assert.Nil(t, first.builder)
+	c.Provide(newReportBuilder, Prototype)
+	assert.NotNil(t, first.builder)
+	assert.Nil(t, c.Validate())
+}
+
+func TestFreshTagOption(t *testing.T) {
+	c := NewContainer()
+	c.Provide(newReportBuilder)
+	shared := &reportService{}
+	first := &freshReportService{}
+	second := &freshReportService{}
+	c.Autowire(&fake.Foo{}, shared)
+	assert.Nil(t, c.TryAutowire(first))
+	assert.Nil(t, c.TryAutowireNamed("second", second))
+	assert.NotSame(t, shared.builder, first.builder)
+	assert.NotSame(t, first.builder, second.builder)
+	assert.Equal(t, []string{"header"}, first.builder.lines)
+	assert.NotNil(t, first.buffer)
+	assert.NotSame(t, first.buffer, second.buffer)
+	assert.NotSame(t, c.Autowired(fake.Foo{}), first.buffer)
+}
+
+func TestFreshTagOptionCycle(t *testing.T) {
+	c := NewContainer()
+	err := c.TryAutowire(&selfPrototype{})
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+}
+
+func Test_parseTagNew(t *testing.T) {
+	assert.True(t, parseTag(",new").fresh)
+	assert.True(t, parseTag("service/AuditService,new").fresh)
+	assert.False(t, parseTag("service/AuditService").fresh)
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type namedFooClient struct {
+	primary *fake.Foo   `autowire:""`
+	replica *fake.Foo   `autowire:"name=replicaFoo"`
+	Passer  fake.Passer `autowire:"name=replicaFoo"`
+}
+
+type namedMismatchClient struct {
+	Invalid fake.InvalidInterface `autowire:"name=replicaFoo"`
+}
+
+func TestAutowireNamed(t *testing.T) {
+	c := NewContainer()
+	primary := &fake.Foo{Name: "primary"}
+	replica := &fake.Foo{Name: "replica"}
+	c.Autowire(primary)
+	c.AutowireNamed("replicaFoo", replica)
+	client := &namedFooClient{}
+	c.Autowire(client)
+	assert.Equal(t, primary, client.primary)
+	assert.Equal(t, replica, client.replica)
+	assert.Equal(t, replica, client.Passer)
+	assert.Equal(t, primary, c.Autowired(fake.Foo{}))
+	assert.Equal(t, replica, c.Autowired("replicaFoo"))
+	assert.Equal(t, 0, len(c.Close()))
+	assert.Equal(t, 1, replica.CloseCalls)
+	assert.Nil(t, c.Autowired("replicaFoo"))
+}
+
+func TestAutowireNamedUnordered(t *testing.T) {
+	c := NewContainer()
+	client := &namedFooClient{}
+	c.Autowire(client)
+	assert.Nil(t, client.replica)
+	replica := &fake.Foo{Name: "replica"}
+	c.AutowireNamed("replicaFoo", replica)
+	assert.Equal(t, replica, client.replica)
+	assert.Equal(t, replica, client.Passer)
+	assert.Nil(t, client.primary)
+	primary := &fake.Foo{Name: "primary"}
+	c.Autowire(primary)
+	assert.Equal(t, primary, client.primary)
+}
+
+func TestAutowireNamedAlreadyAutowired(t *testing.T) {
+	c := NewContainer()
+	first := &fake.Foo{Name: "first"}
+	c.AutowireNamed("foo", first)
+	c.AutowireNamed("foo", &fake.Foo{Name: "second"})
+	c.AutowireNamed("foo", &Foo{})
+	assert.Equal(t, first, c.Autowired("foo"))
+}
+
+func TestAutowireNamedMismatch(t *testing.T) {
+	c := NewContainer()
+	c.AutowireNamed("replicaFoo", &fake.Foo{})
+	err := c.TryAutowire(&namedMismatchClient{})
+	assert.True(t, errors.Is(err, ErrInterfaceMismatch))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "name=replicaFoo", wiringErr.Tag)
+}
+
+func TestAutowiredNamedNotFound(t *testing.T) {
+	c := NewContainer()
+	_, err := c.TryAutowired("replicaFoo")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Nil(t, c.Autowired("replicaFoo"))
+	assert.True(t, errors.Is(c.TryAutowireNamed("foo", fake.Foo{}), ErrNotPointer))
+}
+
+func TestAutowireNamedDefaultContainer(t *testing.T) {
+	replica := &fake.Foo{}
+	AutowireNamed("replicaFoo", replica)
+	assert.Nil(t, TryAutowireNamed("otherFoo", &fake.Foo{}))
+	assert.Equal(t, replica, Autowired("replicaFoo"))
+	assert.Nil(t, Autowired(fake.Foo{}))
+	assert.Equal(t, 0, len(Close()))
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type optionalDependencies struct {
+	Passer fake.Passer `autowire:"fake/Foo,optional"`
+	MyFoo  *fake.Foo   `autowire:",optional"`
+	byType fake.Passer `autowire:",optional"`
+}
+
+type requiredDependencies struct {
+	Passer   fake.Passer `autowire:"fake/Foo"`
+	MyFoo    *fake.Foo   `autowire:""`
+	Optional fake.Passer `autowire:"name=optionalFoo,optional"`
+}
+
+func TestAutowireOptional(t *testing.T) {
+	c := NewContainer()
+	optional := &optionalDependencies{}
+	c.Autowire(optional)
+	assert.Nil(t, optional.Passer)
+	assert.Nil(t, optional.MyFoo)
+	assert.Nil(t, optional.byType)
+	assert.Nil(t, c.Validate())
+
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	assert.Equal(t, foo, optional.Passer)
+	assert.Equal(t, foo, optional.MyFoo)
+	assert.Equal(t, foo, optional.byType)
+	assert.Nil(t, c.Validate())
+}
+
+func TestValidateRequired(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&requiredDependencies{})
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/requiredDependencies", wiringErr.Struct)
+	assert.Equal(t, "MyFoo", wiringErr.Field)
+	assert.Equal(t, packageName+"/internal/fake/Foo", wiringErr.Dependency)
+
+	c.Autowire(&fake.Foo{})
+	assert.Nil(t, c.Validate())
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+const (
+	// ProfilesEnv represents environment variable holding comma separated list of active profiles.
+	ProfilesEnv = "AUTOWIRE_PROFILES"
+	// TestProfile represents the profile active by default inside go tests.
+	TestProfile = "test"
+)
+
+// SetProfiles function activates the profiles of the default Container, e.g. dev,
+// staging, prod or integration, overriding AUTOWIRE_PROFILES environment variable.
+// More than one profile could be active at once. For each configuration file loaded
+// by LoadConfig function, e.g. application.yaml, the overlay files of the active
+// profiles, e.g. application-staging.yaml, are merged on top of it in the order of
+// the profiles, so the configuration is reloaded when the profiles change.
+func SetProfiles(profiles ...string) error {
+	return defaultContainer.SetProfiles(profiles...)
+}
+
+// ActiveProfiles function returns the profiles active inside the default Container.
+func ActiveProfiles() []string {
+	return defaultContainer.ActiveProfiles()
+}
+
+// SetProfiles method activates the profiles of the Container. For more information
+// take a look at SetProfiles function.
+func (c *Container) SetProfiles(profiles ...string) error {
+	return c.update(func() error {
+		c.profiles = append([]string{}, profiles...)
+		if err := c.rebuildProperties(); err != nil {
+			return err
+		}
+		return c.refreshValues()
+	})
+}
+
+// ActiveProfiles method returns the profiles active inside the Container, which are
+// either set by SetProfiles method, inherited from the parent or taken from
+// AUTOWIRE_PROFILES environment variable. When none of them is set, test profile
+// is active inside go tests.
+func (c *Container) ActiveProfiles() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.activeProfiles()
+}
+
+// activeProfiles method returns the active profiles. The caller is expected to hold the lock.
+func (c *Container) activeProfiles() []string {
+	if c.profiles != nil {
+		return append([]string{}, c.profiles...)
+	}
+	if c.parent != nil {
+		c.parent.mu.RLock()
+		defer c.parent.mu.RUnlock()
+		return c.parent.activeProfiles()
+	}
+	var profiles []string
+	for _, profile := range strings.Split(os.Getenv(ProfilesEnv), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	if len(profiles) == 0 && currentProfile == internal.Testing {
+		profiles = []string{TestProfile}
+	}
+	return profiles
+}
+
+// isProfileActive method reports whether the profile is active. The caller is expected to hold the lock.
+func (c *Container) isProfileActive(profile string) bool {
+	for _, active := range c.activeProfiles() {
+		if active == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// overlayPath function returns the path of the profile overlay of the configuration
+// file, e.g. application-staging.yaml for application.yaml.
+func overlayPath(path string, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + profile + ext
+}
+
+// loadOverlays method returns properties of the base configuration file merged with
+// the overlays of the active profiles. Missing overlays are skipped.
+// The caller is expected to hold the lock.
+func (c *Container) loadOverlays(source configSource) (map[string]string, error) {
+	properties := make(map[string]string, len(source.properties))
+	for key, value := range source.properties {
+		properties[key] = value
+	}
+	if source.path == "" {
+		return properties, nil
+	}
+	for _, profile := range c.activeProfiles() {
+		overlay, err := parseConfigFile(overlayPath(source.path, profile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, &WiringError{Err: ErrInvalidConfig, Dependency: overlayPath(source.path, profile), Cause: err}
+		}
+		for key, value := range overlay {
+			properties[key] = value
+		}
+	}
+	return properties, nil
+}
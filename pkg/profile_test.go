@@ -0,0 +1,44 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveProfiles(t *testing.T) {
+	t.Setenv(ProfilesEnv, "dev, integration,")
+	c := NewContainer()
+	assert.Equal(t, []string{"dev", "integration"}, c.ActiveProfiles())
+	child := c.NewChild()
+	assert.Nil(t, c.SetProfiles("prod"))
+	assert.Equal(t, []string{"prod"}, c.ActiveProfiles())
+	assert.Equal(t, []string{"prod"}, child.ActiveProfiles())
+	assert.Nil(t, c.SetProfiles())
+	assert.Equal(t, 0, len(c.ActiveProfiles()))
+}
+
+func TestProfileOverlays(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.SetProfiles("staging"))
+	server := &serverConfig{}
+	service := &configService{}
+	c.Autowire(server, service)
+	assert.Nil(t, c.LoadConfig("testdata/application.yaml"))
+	assert.Equal(t, 8443, server.Port)
+	assert.Equal(t, "staging", service.apiKey)
+	assert.True(t, server.TLS.Enabled)
+
+	assert.Nil(t, c.SetProfiles("staging", "debug", "missing"))
+	assert.Equal(t, 9443, server.Port)
+	assert.Equal(t, "staging", service.apiKey)
+
+	assert.Nil(t, c.SetProfiles())
+	assert.Equal(t, 8080, server.Port)
+	assert.Equal(t, "file", service.apiKey)
+}
+
+func Test_overlayPath(t *testing.T) {
+	assert.Equal(t, "config/application-dev.yaml", overlayPath("config/application.yaml", "dev"))
+	assert.Equal(t, "conf.d/application-dev", overlayPath("conf.d/application", "dev"))
+}
@@ -0,0 +1,180 @@
+package pkg
+
+import (
+	"errors"
+	"log"
+	"reflect"
+)
+
+// provider holds constructor registered via Provide function.
+type provider struct {
+	constructor reflect.Value
+	resultType  reflect.Type
+	lifetime    Lifetime
+}
+
+// Provide function registers constructor inside the default Container. The
+// constructor is a function returning struct pointer and optionally an error
+// as the second result, e.g.:
+//  func NewUserService(cfg *ApplicationConfig, repo UserRoleRepository) (*UserService, error)
+// All the arguments of the constructor should be struct pointers or interfaces,
+// which are resolved from the dependency graph. The constructor is invoked
+// lazily, when the dependency is requested for the first time, afterwards the
+// result is autowired and registered under its full path like it was passed to
+// Autowire function. In case any argument isn't registered yet, the structs waiting
+// for the result are injected once the argument is registered, so the order of
+// init functions doesn't matter. Error returned by the constructor is reported as *WiringError
+// caused by ErrConstructorFailed.
+// The constructor could be registered with options, e.g. Prototype lifetime or
+// conditions, take a look at Condition type:
+//  pkg.Provide(NewReportBuilder, pkg.Prototype)
+//  pkg.Provide(NewPaypalService, pkg.OnProfile("prod"))
+// The constructor is invoked while the Container is locked, so it shouldn't use the
+// Container, e.g. via Get, Autowired or Lazy fields, as it would never return. Its
+// dependencies should be declared as arguments instead, while the rest of the work
+// could be done in Init method of Initializer interface, which is invoked without the lock.
+// Provide panics in case constructor has unsupported signature.
+func Provide(constructor interface{}, options ...Option) {
+	defaultContainer.Provide(constructor, options...)
+}
+
+// TryProvide function works like Provide function, but instead of panicking
+// returns *WiringError caused by ErrInvalidConstructor.
+func TryProvide(constructor interface{}, options ...Option) error {
+	return defaultContainer.TryProvide(constructor, options...)
+}
+
+// Provide method registers constructor inside the Container. For more
+// information take a look at Provide function.
+func (c *Container) Provide(constructor interface{}, options ...Option) {
+	if err := c.TryProvide(constructor, options...); err != nil {
+		log.Panicln(err.Error())
+	}
+}
+
+// TryProvide method works like Provide method, but instead of panicking
+// returns *WiringError caused by ErrInvalidConstructor.
+func (c *Container) TryProvide(constructor interface{}, options ...Option) error {
+	p, err := newProvider(constructor)
+	if err != nil {
+		return err
+	}
+	r := newRegistration(options)
+	p.lifetime = r.lifetime
+	return c.update(func() error {
+		if c.deferRegistration(pendingRegistration{provider: p, conditions: r.conditions}) {
+			return nil
+		}
+		return c.addProvider(p)
+	})
+}
+
+// addProvider method registers the provider. The caller is expected to hold the lock.
+func (c *Container) addProvider(p *provider) error {
+	path := getFullPath(p.resultType.Elem().PkgPath(), p.resultType.String())
+	if _, ok := c.dependencies[path]; ok {
+		log.Printf("%s already autowired... ignored", path)
+		return nil
+	}
+	if _, ok := c.providers[path]; ok {
+		log.Printf("%s already provided... ignored", path)
+		return nil
+	}
+	log.Printf("Providing %s", path)
+	c.providers[path] = p
+	c.constructors[path] = p
+	c.addSequence(path)
+	if p.lifetime == Scoped {
+		return nil
+	}
+	if err := c.resolveUninitialized(path, "", p.resultType); err != nil {
+		return err
+	}
+	return c.resolveCollections(p.resultType)
+}
+
+func newProvider(constructor interface{}) (*provider, error) {
+	value := reflect.ValueOf(constructor)
+	if value.Kind() != reflect.Func {
+		return nil, &WiringError{Err: ErrInvalidConstructor, Dependency: typeName(value)}
+	}
+	t := value.Type()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if t.NumOut() < 1 || t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != errorType) ||
+		t.Out(0).Kind() != reflect.Ptr || t.Out(0).Elem().Kind() != reflect.Struct || t.IsVariadic() {
+		return nil, &WiringError{Err: ErrInvalidConstructor, Dependency: t.String()}
+	}
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if in.Kind() != reflect.Interface && (in.Kind() != reflect.Ptr || in.Elem().Kind() != reflect.Struct) {
+			return nil, &WiringError{Err: ErrInvalidConstructor, Dependency: t.String()}
+		}
+	}
+	return &provider{constructor: value, resultType: t.Out(0)}, nil
+}
+
+// construct method resolves arguments of the provider and invokes the constructor.
+// The caller is expected to hold the lock.
+func (c *Container) construct(p *provider) (interface{}, error) {
+	t := p.constructor.Type()
+	path := getFullPath(p.resultType.Elem().PkgPath(), p.resultType.String())
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		key, dependency, err := c.resolveType(t.In(i))
+		if err != nil {
+			return nil, c.argumentError(path, t.In(i), err)
+		}
+		if key == "" {
+			args[i] = reflect.ValueOf(dependency)
+			continue
+		}
+		if cycle := c.addEdge(path, key, false); cycle != nil {
+			return nil, &WiringError{Err: ErrCircularDependency, Struct: path, Cycle: cycle}
+		}
+		args[i] = reflect.ValueOf(dependency)
+	}
+	return p.invoke(path, args)
+}
+
+// invoke method calls the constructor with the resolved arguments.
+func (p *provider) invoke(path string, args []reflect.Value) (interface{}, error) {
+	log.Printf("Constructing %s", path)
+	results := p.constructor.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return nil, &WiringError{Err: ErrConstructorFailed, Struct: path, Cause: results[1].Interface().(error)}
+	}
+	if results[0].IsNil() {
+		return nil, &WiringError{Err: ErrConstructorFailed, Struct: path}
+	}
+	return results[0].Interface(), nil
+}
+
+// argumentError method reports the missing argument of the constructor under its full
+// path, so the structs waiting for the constructed dependency could be parked on the
+// argument until it's registered. In case of interface, the interface is remembered
+// for the same purpose. The caller is expected to hold the lock.
+func (c *Container) argumentError(path string, t reflect.Type, err error) error {
+	var depName string
+	if t.Kind() == reflect.Interface {
+		depName = getFullPath(t.PkgPath(), t.String())
+	} else {
+		depName = getFullPath(t.Elem().PkgPath(), t.String())
+	}
+	if !isMissing(err, t.String()) && !isMissing(err, depName) {
+		return err
+	}
+	if t.Kind() == reflect.Interface {
+		c.interfaces[depName] = t
+	}
+	return &WiringError{Err: ErrNotFound, Struct: path, Dependency: depName}
+}
+
+// pendingDependency function returns the full path of the missing dependency, which
+// caused the error, either the dependency itself or any of the constructor arguments.
+func pendingDependency(err error) (string, bool) {
+	var wiringErr *WiringError
+	if !errors.As(err, &wiringErr) || wiringErr.Err != ErrNotFound {
+		return "", false
+	}
+	return wiringErr.Dependency, true
+}
@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+var errInvalidName = errors.New("invalid name")
+
+type namedConfig struct {
+	name string
+}
+
+type namedService struct {
+	config *namedConfig
+	passer fake.Passer
+	MyFoo  *fake.Foo `autowire:""`
+}
+
+type namedServiceClient struct {
+	service *namedService `autowire:""`
+}
+
+func newNamedService(config *namedConfig, passer fake.Passer) (*namedService, error) {
+	if config.name == "" {
+		return nil, errInvalidName
+	}
+	return &namedService{config: config, passer: passer}, nil
+}
+
+func TestProvideInvalidConstructor(t *testing.T) {
+	c := NewContainer()
+	invalid := []interface{}{
+		nil,
+		&fake.Foo{},
+		func() {},
+		func() fake.Foo { return fake.Foo{} },
+		func() (*fake.Foo, string) { return nil, "" },
+		func() (*fake.Foo, error, error) { return nil, nil, nil },
+		func(name string) *fake.Foo { return nil },
+		func(foos ...*fake.Foo) *fake.Foo { return nil },
+	}
+	for _, constructor := range invalid {
+		assert.True(t, errors.Is(c.TryProvide(constructor), ErrInvalidConstructor))
+	}
+	assert.Panics(t, func() {
+		c.Provide(func() {})
+	})
+}
+
+func TestProvideLazy(t *testing.T) {
+	c := NewContainer()
+	calls := 0
+	c.Provide(func() *namedConfig {
+		calls++
+		return &namedConfig{name: "test"}
+	})
+	c.Provide(newNamedService)
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	assert.Equal(t, 0, calls)
+
+	service, ok := GetFrom[*namedService](c)
+	assert.True(t, ok)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "test", service.config.name)
+	assert.Equal(t, foo, service.passer)
+	assert.Equal(t, foo, service.MyFoo)
+	assert.Equal(t, service, c.Autowired(namedService{}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestProvideConstructorError(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&namedConfig{}, &fake.Foo{})
+	c.Provide(newNamedService)
+	_, err := c.TryAutowired(namedService{})
+	assert.True(t, errors.Is(err, ErrConstructorFailed))
+	assert.True(t, errors.Is(err, errInvalidName))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/namedService", wiringErr.Struct)
+
+	err = c.TryAutowire(&namedServiceClient{})
+	assert.True(t, errors.Is(err, errInvalidName))
+}
+
+func TestProvideMissingArgument(t *testing.T) {
+	c := NewContainer()
+	c.Provide(newNamedService)
+	_, err := c.TryAutowired(&namedService{})
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestProvideFieldInjection(t *testing.T) {
+	c := NewContainer()
+	c.Provide(newNamedService)
+	c.Autowire(&namedConfig{name: "test"}, &fake.Foo{})
+	client := &namedServiceClient{}
+	c.Autowire(client)
+	assert.NotNil(t, client.service)
+	assert.Equal(t, client.service, c.Autowired(namedService{}))
+}
+
+func TestProvideResolvesUninitialized(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&namedConfig{name: "test"}, &fake.Foo{})
+	client := &namedServiceClient{}
+	c.Autowire(client)
+	assert.Nil(t, client.service)
+	c.Provide(newNamedService)
+	assert.NotNil(t, client.service)
+}
+
+func TestProvideInterface(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&namedConfig{name: "test"})
+	c.Provide(func(config *namedConfig) *fake.Foo {
+		return &fake.Foo{Name: config.name}
+	})
+	passer := MustGetFrom[fake.Passer](c)
+	assert.Equal(t, "test", passer.(*fake.Foo).Name)
+}
+
+func TestProvideAlreadyAutowired(t *testing.T) {
+	c := NewContainer()
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	assert.Nil(t, c.TryProvide(func() *fake.Foo { return &fake.Foo{Name: "provided"} }))
+	assert.Equal(t, foo, c.Autowired(fake.Foo{}))
+}
+
+func TestProvideDefaultContainer(t *testing.T) {
+	Provide(func() *namedConfig { return &namedConfig{name: "default"} })
+	assert.True(t, errors.Is(TryProvide(nil), ErrInvalidConstructor))
+	config := MustGet[*namedConfig]()
+	assert.Equal(t, "default", config.name)
+	assert.Equal(t, 0, len(Close()))
+}
+
+func TestProvidePendingArgument(t *testing.T) {
+	c := NewContainer()
+	client := &namedServiceClient{}
+	c.Autowire(client)
+	assert.Nil(t, c.TryProvide(newNamedService))
+	assert.Nil(t, client.service)
+	c.Autowire(&fake.Foo{})
+	assert.Nil(t, client.service)
+	c.Autowire(&namedConfig{name: "test"})
+	assert.NotNil(t, client.service)
+	assert.Nil(t, c.Validate())
+}
+
+func TestProvideBeforePendingArgument(t *testing.T) {
+	c := NewContainer()
+	c.Provide(newNamedService)
+	client := &namedServiceClient{}
+	c.Autowire(client, &fake.Foo{})
+	assert.Nil(t, client.service)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(c.Validate(), &validationErr))
+	for _, err := range validationErr.Errors {
+		assert.Equal(t, packageName+"/namedConfig", err.Dependency)
+	}
+	c.Autowire(&namedConfig{name: "test"})
+	assert.NotNil(t, client.service)
+	assert.Equal(t, "test", client.service.config.name)
+}
@@ -0,0 +1,161 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownGracePeriod represents the time the shutdown started by Run function
+// could take, unless changed by SetShutdownGracePeriod function.
+const DefaultShutdownGracePeriod = 30 * time.Second
+
+// Starter represents struct, which should be started by Run function, e.g. http
+// server starting to listen. Start method shouldn't block, long-running work
+// should be done in background until the context is done or the struct is stopped.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// RunError represents failures occurred during Run function. RunError could be
+// checked with errors.Is and errors.As functions, which are matching any of the failures.
+type RunError struct {
+	// Start holds the failure of Start method, if any.
+	Start *WiringError
+	// Shutdown holds the failures occurred during shutdown, if any.
+	Shutdown *ShutdownError
+}
+
+// Error returns description of all the failures.
+func (e *RunError) Error() string {
+	var failures []string
+	for _, err := range e.failures() {
+		failures = append(failures, err.Error())
+	}
+	return strings.Join(failures, "\n")
+}
+
+// Is reports whether any of the failures matches the target.
+func (e *RunError) Is(target error) bool {
+	return e.failures().is(target)
+}
+
+// As finds the first failure matching the target.
+func (e *RunError) As(target interface{}) bool {
+	return e.failures().as(target)
+}
+
+// failures method returns aggregate of the failures, which occurred.
+func (e *RunError) failures() aggregate {
+	var failures aggregate
+	if e.Start != nil {
+		failures = append(failures, e.Start)
+	}
+	if e.Shutdown != nil {
+		failures = append(failures, e.Shutdown)
+	}
+	return failures
+}
+
+// Run function finalizes conditional registrations of the default Container and
+// invokes Start method on each struct registered inside it, which implements Starter
+// interface, in dependency order, so each struct is started after all of its
+// dependencies. Then Run function blocks until SIGINT
+// or SIGTERM signal arrives or the context is done, and gracefully stops all the
+// structs using Shutdown function. In case any Start method fails, the remaining
+// structs are not started and the shutdown begins immediately. Returning *RunError
+// holding failures of both Start and Shutdown, so main function becomes:
+//  func main() {
+//      if err := pkg.Run(context.Background()); err != nil {
+//          log.Fatalln(err)
+//      }
+//  }
+// The time given to each struct to stop could be limited by SetShutdownTimeout function,
+// while the whole shutdown is limited by SetShutdownGracePeriod function. Once the signal
+// arrived, another one terminates the process immediately.
+func Run(ctx context.Context) error {
+	return defaultContainer.Run(ctx)
+}
+
+// Run method starts structs registered inside the Container and stops them once
+// signal arrives or the context is done. For more information take a look at Run function.
+func (c *Container) Run(ctx context.Context) error {
+	if err := c.Finalize(); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var runErr RunError
+	if runErr.Start = c.start(ctx); runErr.Start == nil {
+		<-ctx.Done()
+		log.Println("Stopping...")
+	}
+	// Restore default handling, so another signal terminates the process during shutdown.
+	stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.shutdownGracePeriod())
+	defer cancel()
+	if err := c.Shutdown(shutdownCtx); err != nil {
+		errors.As(err, &runErr.Shutdown)
+	}
+	if runErr.Start == nil && runErr.Shutdown == nil {
+		return nil
+	}
+	return &runErr
+}
+
+// SetShutdownGracePeriod function limits the time the shutdown of the default Container
+// started by Run function could take. Structs, which weren't stopped before it elapses,
+// are reported as *WiringError caused by ErrShutdownTimeout. Zero, which is the default,
+// means DefaultShutdownGracePeriod.
+func SetShutdownGracePeriod(gracePeriod time.Duration) {
+	defaultContainer.SetShutdownGracePeriod(gracePeriod)
+}
+
+// SetShutdownGracePeriod method limits the time the shutdown of the Container started by
+// Run method could take. For more information take a look at SetShutdownGracePeriod function.
+func (c *Container) SetShutdownGracePeriod(gracePeriod time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gracePeriod = gracePeriod
+}
+
+// shutdownGracePeriod method returns the time the shutdown started by Run method could take.
+func (c *Container) shutdownGracePeriod() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.gracePeriod > 0 {
+		return c.gracePeriod
+	}
+	return DefaultShutdownGracePeriod
+}
+
+// start method invokes Start method of the dependencies in dependency order and
+// returns the first failure. Start methods are invoked without holding the lock.
+func (c *Container) start(ctx context.Context) *WiringError {
+	c.mu.RLock()
+	levels := c.dependencyLevels()
+	dependencies := make(map[string]interface{}, len(c.dependencies))
+	for key, dependency := range c.dependencies {
+		dependencies[key] = dependency
+	}
+	c.mu.RUnlock()
+	for _, level := range levels {
+		for _, key := range level {
+			starter, ok := dependencies[key].(Starter)
+			if !ok {
+				continue
+			}
+			log.Printf("Starting %s", key)
+			if err := starter.Start(ctx); err != nil {
+				log.Println(err.Error())
+				return &WiringError{Err: ErrStartFailed, Struct: key, Cause: err}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errStart = errors.New("start error")
+
+type startRepository struct {
+	recorder *stopRecorder
+}
+
+func (r *startRepository) Start(context.Context) error {
+	r.recorder.record("start repository")
+	return nil
+}
+
+func (r *startRepository) Stop(context.Context) error {
+	r.recorder.record("stop repository")
+	return nil
+}
+
+type startServer struct {
+	recorder   *stopRecorder
+	repository *startRepository `autowire:""`
+	err        error
+}
+
+func (s *startServer) Start(context.Context) error {
+	s.recorder.record("start server")
+	return s.err
+}
+
+func (s *startServer) Stop(context.Context) error {
+	s.recorder.record("stop server")
+	return nil
+}
+
+type hangingServer struct {
+	release chan struct{}
+}
+
+// Stop method ignores the context and returns only once the test releases it.
+func (s *hangingServer) Stop(context.Context) error {
+	<-s.release
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	c := NewContainer()
+	recorder := &stopRecorder{}
+	c.Autowire(&startServer{recorder: recorder}, &startRepository{recorder: recorder})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, c.Run(ctx))
+	assert.Equal(t, []string{"start repository", "start server", "stop server", "stop repository"}, recorder.calls)
+	assert.Nil(t, c.Autowired(startServer{}))
+}
+
+func TestRunStartError(t *testing.T) {
+	c := NewContainer()
+	recorder := &stopRecorder{}
+	c.Autowire(&startServer{recorder: recorder, err: errStart}, &startRepository{recorder: recorder},
+		&stopCache{recorder: recorder, err: errClose})
+	err := c.Run(context.Background())
+	assert.True(t, errors.Is(err, ErrStartFailed))
+	assert.True(t, errors.Is(err, errStart))
+	assert.True(t, errors.Is(err, ErrCloseFailed))
+	assert.True(t, errors.Is(err, errClose))
+	var runErr *RunError
+	assert.True(t, errors.As(err, &runErr))
+	assert.Equal(t, packageName+"/startServer", runErr.Start.Struct)
+	assert.Equal(t, 1, len(runErr.Shutdown.Errors))
+	assert.Equal(t, "start server", recorder.calls[1])
+}
+
+func TestRunShutdownGracePeriod(t *testing.T) {
+	c := NewContainer()
+	c.SetShutdownGracePeriod(10 * time.Millisecond)
+	server := &hangingServer{release: make(chan struct{})}
+	defer close(server.release)
+	c.Autowire(server)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.Run(ctx)
+	assert.True(t, errors.Is(err, ErrShutdownTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var runErr *RunError
+	assert.True(t, errors.As(err, &runErr))
+	assert.Nil(t, runErr.Start)
+	assert.Equal(t, packageName+"/hangingServer", runErr.Shutdown.Errors[0].Struct)
+}
@@ -0,0 +1,325 @@
+package pkg
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// contextType represents context.Context interface, which is resolved as the context of the Scope.
+//nolint:gochecknoglobals
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// scopeKey represents the key of the Scope attached to the context.
+type scopeKey struct{}
+
+// Scope represents resolution context of the dependencies provided with Scoped
+// lifetime, e.g. per request transaction or request logger. Dependency provided
+// with Scoped lifetime is constructed once per Scope and closed when the Scope is
+// closed, while the rest of the dependencies are resolved from the Container.
+// Scope is safe for concurrent use by multiple goroutines. Scoped dependencies are
+// constructed without locking the Container, so the Scopes don't block each other.
+type Scope struct {
+	mu           sync.Mutex
+	container    *Container
+	ctx          context.Context
+	instances    map[string]interface{}
+	order        []string
+	constructing []string
+	closed       bool
+}
+
+// NewScope function returns new Scope of the default Container bound to the
+// context. For more information take a look at Scoped lifetime.
+func NewScope(ctx context.Context) *Scope {
+	return defaultContainer.NewScope(ctx)
+}
+
+// NewScope method returns new Scope of the Container bound to the context.
+func (c *Container) NewScope(ctx context.Context) *Scope {
+	s := &Scope{container: c, instances: make(map[string]interface{})}
+	s.ctx = context.WithValue(ctx, scopeKey{}, s)
+	return s
+}
+
+// ScopeFromContext function returns the Scope attached to the context by Middleware
+// function, or nil when there is none.
+func ScopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// Middleware function wraps the http handler, so each request gets its own Scope
+// of the default Container, which is attached to the request context and closed once
+// the request is handled. Dependencies provided with Scoped lifetime could be
+// retrieved inside the handler:
+//  http.Handle("/users", pkg.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//      tx := pkg.MustGetFromScope[*Transaction](pkg.ScopeFromContext(r.Context()))
+//  })))
+func Middleware(next http.Handler) http.Handler {
+	return defaultContainer.Middleware(next)
+}
+
+// Middleware method wraps the http handler, so each request gets its own Scope of
+// the Container. For more information take a look at Middleware function.
+func (c *Container) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := c.NewScope(r.Context())
+		defer s.Close()
+		next.ServeHTTP(w, r.WithContext(s.Context()))
+	})
+}
+
+// GetFromScope function works like Get function, but looks up the dependency inside
+// the given Scope, so dependencies provided with Scoped lifetime could be resolved.
+func GetFromScope[T any](s *Scope) (T, bool) {
+	result, err := getFrom[T](s)
+	return result, err == nil
+}
+
+// MustGetFromScope function works like MustGet function, but looks up the dependency inside the given Scope.
+func MustGetFromScope[T any](s *Scope) T {
+	result, err := getFrom[T](s)
+	if err != nil {
+		log.Panicln(err.Error())
+	}
+	return result
+}
+
+// Context method returns the context of the Scope, which holds the Scope itself.
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Autowire method injects all dependencies for the given structures and registers
+// them inside the Scope, e.g. currently authenticated user, so they could be injected
+// into struct pointer fields of dependencies provided with Scoped lifetime.
+func (s *Scope) Autowire(values ...interface{}) {
+	if err := s.TryAutowire(values...); err != nil {
+		log.Panicln(err.Error())
+	}
+}
+
+// TryAutowire method works like Autowire method, but instead of panicking
+// returns *WiringError describing the failure.
+func (s *Scope) TryAutowire(values ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return &WiringError{Err: ErrOutOfScope}
+	}
+	for _, v := range values {
+		value := reflect.ValueOf(v)
+		if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+			return &WiringError{Err: ErrNotPointer, Dependency: typeName(value)}
+		}
+		path := getStructPtrFullPath(value)
+		if _, ok := s.instances[path]; ok {
+			log.Printf("%s already autowired in scope... ignored", path)
+			continue
+		}
+		if err := s.wire(value, path); err != nil {
+			return err
+		}
+		s.store(path, v)
+	}
+	return nil
+}
+
+// Close method invoke Close method on each struct constructed inside the Scope,
+// which implements io.Closer interface, in reverse construction order. Returning
+// slice of occurred errors, each of them is *WiringError caused by ErrCloseFailed.
+// Closed Scope couldn't be used anymore.
+func (s *Scope) Close() []error {
+	s.mu.Lock()
+	order := s.order
+	instances := s.instances
+	s.closed = true
+	s.order = nil
+	s.instances = make(map[string]interface{})
+	s.mu.Unlock()
+	var errors []error
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := closeDependency(order[i], instances[order[i]]); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+// lookupType method returns the dependency assignable to the given type resolved inside the Scope.
+func (s *Scope) lookupType(t reflect.Type) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, &WiringError{Err: ErrOutOfScope, Dependency: t.String()}
+	}
+	return s.resolveType(t)
+}
+
+// resolveType method returns the dependency assignable to the given type. Instances of the
+// Scope and registered singletons are looked up holding only the read lock of the Container,
+// while Scoped dependencies are constructed without holding its lock at all. The rest is
+// resolved by the Container. The caller is expected to hold the lock of the Scope.
+func (s *Scope) resolveType(t reflect.Type) (interface{}, error) {
+	if t == contextType {
+		return s.ctx, nil
+	}
+	key, dependency, p := s.lookup(t, tagOptions{})
+	switch {
+	case dependency != nil:
+		return dependency, nil
+	case p != nil:
+		return s.construct(key, p)
+	}
+	return s.container.lookupType(t)
+}
+
+// resolve method returns the dependency of type t matching the tag options resolved inside
+// the Scope, e.g. by Lazy field of the struct wired inside the Scope.
+func (s *Scope) resolve(t reflect.Type, options tagOptions) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, &WiringError{Err: ErrOutOfScope, Dependency: t.String()}
+	}
+	if t == contextType {
+		return s.ctx, nil
+	}
+	key, dependency, p := s.lookup(t, options)
+	switch {
+	case dependency != nil:
+		return dependency, nil
+	case p != nil:
+		return s.construct(key, p)
+	}
+	_, dependency, err := s.container.resolveInherited(t, options)
+	return dependency, err
+}
+
+// lookup method returns either the dependency of type t matching the tag options, which is
+// already registered, or the provider with Scoped lifetime, which should be constructed.
+// The caller is expected to hold the lock of the Scope.
+func (s *Scope) lookup(t reflect.Type, options tagOptions) (string, interface{}, *provider) {
+	c := s.container
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var candidates []string
+	switch {
+	case options.name != "":
+		if key, ok := c.names[options.name]; ok {
+			candidates = []string{key}
+		}
+	case options.qualifier != "":
+		candidates = c.findDependency(options.qualifier)
+	case t.Kind() == reflect.Interface:
+		candidates = c.implementations(t, true)
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		candidates = []string{getFullPath(t.Elem().PkgPath(), t.String())}
+	}
+	if len(candidates) != 1 {
+		return "", nil, nil
+	}
+	key := candidates[0]
+	if instance, ok := s.instances[key]; ok {
+		return key, instance, nil
+	}
+	if dependency, ok := c.dependencies[key]; ok {
+		return key, dependency, nil
+	}
+	if p, ok := c.providers[key]; ok && p.lifetime == Scoped {
+		return key, nil, p
+	}
+	return "", nil, nil
+}
+
+// construct method constructs the dependency provided with Scoped lifetime and stores it
+// inside the Scope. The constructor is invoked without holding the lock of the Container.
+// The caller is expected to hold the lock of the Scope.
+func (s *Scope) construct(path string, p *provider) (interface{}, error) {
+	for i, constructing := range s.constructing {
+		if constructing == path {
+			cycle := append(append([]string{}, s.constructing[i:]...), path)
+			return nil, &WiringError{Err: ErrCircularDependency, Struct: path, Cycle: cycle}
+		}
+	}
+	s.constructing = append(s.constructing, path)
+	defer func() {
+		s.constructing = s.constructing[:len(s.constructing)-1]
+	}()
+	t := p.constructor.Type()
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		dependency, err := s.resolveType(t.In(i))
+		if err != nil {
+			return nil, err
+		}
+		args[i] = reflect.ValueOf(dependency)
+	}
+	instance, err := p.invoke(path, args)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.wire(reflect.ValueOf(instance), path); err != nil {
+		return nil, err
+	}
+	s.store(path, instance)
+	return instance, nil
+}
+
+// wire method injects the dependencies of the struct. Scoped dependencies of the tagged
+// fields are constructed first, so the lock of the Container is held only while the
+// fields are injected. The caller is expected to hold the lock of the Scope.
+func (s *Scope) wire(value reflect.Value, path string) error {
+	elem := value.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Type().Field(i)
+		tag, ok := field.Tag.Lookup(Tag)
+		if !ok {
+			continue
+		}
+		if key, dependency, p := s.lookup(field.Type, parseTag(tag)); dependency == nil && p != nil {
+			if _, err := s.construct(key, p); err != nil {
+				return err
+			}
+		}
+	}
+	c := s.container
+	c.mu.Lock()
+	registered := len(c.dependencies)
+	c.scope = s
+	err := c.autowireDependencies(value, path)
+	c.scope = nil
+	constructed := len(c.dependencies) > registered
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if constructed {
+		c.initialize()
+	}
+	return nil
+}
+
+// store method registers the instance inside the Scope. The caller is expected to hold the lock of the Scope.
+func (s *Scope) store(path string, instance interface{}) {
+	s.instances[path] = instance
+	s.order = append(s.order, path)
+}
+
+// resolveScoped method returns the instance of the dependency provided with Scoped lifetime
+// from the current scope, constructing it first if it wasn't constructed by the Scope yet,
+// e.g. in case of collections. The caller is expected to hold the lock.
+func (c *Container) resolveScoped(path string) (interface{}, error) {
+	if c.scope == nil {
+		return nil, &WiringError{Err: ErrOutOfScope, Dependency: path}
+	}
+	instance, err := c.newInstance(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.scope.store(path, instance)
+	return instance, nil
+}
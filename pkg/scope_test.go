@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type currentUser struct {
+	id string
+}
+
+type transaction struct {
+	ctx    context.Context
+	foo    *fake.Foo
+	closed bool
+}
+
+func (tx *transaction) Close() error {
+	tx.closed = true
+	return nil
+}
+
+func newTransaction(ctx context.Context, foo *fake.Foo) *transaction {
+	return &transaction{ctx: ctx, foo: foo}
+}
+
+type requestService struct {
+	tx   *transaction `autowire:""`
+	user *currentUser `autowire:""`
+}
+
+func newRequestService() *requestService {
+	return &requestService{}
+}
+
+type leakingSingleton struct {
+	tx *transaction `autowire:""`
+}
+
+func TestScope(t *testing.T) {
+	c := NewContainer()
+	foo := &fake.Foo{}
+	c.Autowire(foo)
+	c.Provide(newTransaction, Scoped)
+	c.Provide(newRequestService, Scoped)
+	assert.Nil(t, c.Validate())
+
+	first := c.NewScope(context.Background())
+	first.Autowire(&currentUser{id: "first"})
+	service := MustGetFromScope[*requestService](first)
+	assert.Equal(t, "first", service.user.id)
+	assert.Same(t, service.tx, MustGetFromScope[*transaction](first))
+	assert.Same(t, foo, service.tx.foo)
+	assert.Same(t, first, ScopeFromContext(service.tx.ctx))
+
+	second := c.NewScope(context.Background())
+	tx, ok := GetFromScope[*transaction](second)
+	assert.True(t, ok)
+	assert.NotSame(t, service.tx, tx)
+
+	assert.Equal(t, 0, len(first.Close()))
+	assert.True(t, service.tx.closed)
+	assert.False(t, tx.closed)
+	_, err := getFrom[*transaction](first)
+	assert.True(t, errors.Is(err, ErrOutOfScope))
+	assert.Equal(t, 0, len(second.Close()))
+	assert.True(t, tx.closed)
+}
+
+func TestScopedOutOfScope(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{})
+	c.Provide(newTransaction, Scoped)
+	_, err := getFrom[*transaction](c)
+	assert.True(t, errors.Is(err, ErrOutOfScope))
+	err = c.TryAutowire(&leakingSingleton{})
+	assert.True(t, errors.Is(err, ErrOutOfScope))
+}
+
+func TestMiddleware(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{})
+	c.Provide(newTransaction, Scoped)
+	var tx *transaction
+	handler := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tx = MustGetFromScope[*transaction](ScopeFromContext(r.Context()))
+		assert.False(t, tx.closed)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.True(t, tx.closed)
+	assert.Nil(t, ScopeFromContext(context.Background()))
+}
+
+type blockingScoped struct{}
+
+func TestScopedConstructedWithoutLockingContainer(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&fake.Foo{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	c.Provide(func() *blockingScoped {
+		close(started)
+		<-release
+		return &blockingScoped{}
+	}, Scoped)
+	c.Provide(newTransaction, Scoped)
+
+	done := make(chan *blockingScoped)
+	go func() {
+		done <- MustGetFromScope[*blockingScoped](c.NewScope(context.Background()))
+	}()
+	<-started
+	c.Autowire(&currentUser{id: "concurrent"})
+	tx, ok := GetFromScope[*transaction](c.NewScope(context.Background()))
+	assert.True(t, ok)
+	assert.NotNil(t, tx.foo)
+	close(release)
+	assert.NotNil(t, <-done)
+}
+
+type scopedPlugin struct{}
+
+func (*scopedPlugin) Plugin() string { return "scoped" }
+
+func TestScopedSkippedByCollections(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func() *scopedPlugin { return &scopedPlugin{} }, Scoped)
+	c.Autowire(&firstPlugin{})
+	registry := &pluginRegistry{}
+	assert.Nil(t, c.TryAutowire(registry))
+	assert.Equal(t, []string{"first"}, registry.names())
+
+	s := c.NewScope(context.Background())
+	assert.Equal(t, "scoped", MustGetFromScope[*scopedPlugin](s).Plugin())
+}
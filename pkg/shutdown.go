@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stopper represents struct, which should be gracefully stopped during Shutdown,
+// e.g. http server draining in-flight requests. Stop method should return once
+// the struct is stopped or the context is done.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// ShutdownError represents all the failures occurred during Shutdown function.
+// ShutdownError could be checked with errors.Is and errors.As functions,
+// which are matching any of the failures.
+type ShutdownError struct {
+	// Errors holds the failures sorted by struct path.
+	Errors []*WiringError
+}
+
+// Error returns description of all the failures, each on separate line.
+func (e *ShutdownError) Error() string {
+	return wiringFailures(e.Errors).describe("failures during shutdown")
+}
+
+// Is reports whether any of the failures matches the target.
+func (e *ShutdownError) Is(target error) bool {
+	return wiringFailures(e.Errors).is(target)
+}
+
+// As finds the first failure matching the target.
+func (e *ShutdownError) As(target interface{}) bool {
+	return wiringFailures(e.Errors).as(target)
+}
+
+// Shutdown function gracefully stops each struct registered inside the default
+// Container, which implements Stopper or io.Closer interface. In case the struct
+// implements both, only Stop method is invoked. Structs are stopped in reverse
+// dependency order, while independent structs are stopped in parallel.
+// Shutdown function honors the deadline of the context, so it fits into the
+// termination grace period:
+//  ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//  defer cancel()
+//  if err := pkg.Shutdown(ctx); err != nil {
+//      log.Println(err)
+//  }
+// Returning *ShutdownError listing the structs, which failed to stop (ErrCloseFailed)
+// or weren't stopped before the deadline (ErrShutdownTimeout).
+// Shutdown function cleans the dependency graph.
+func Shutdown(ctx context.Context) error {
+	return defaultContainer.Shutdown(ctx)
+}
+
+// Shutdown method gracefully stops structs registered inside the Container.
+// For more information take a look at Shutdown function.
+func (c *Container) Shutdown(ctx context.Context) error {
+	log.Println("Shutting down...")
+	c.mu.Lock()
+	levels := c.dependencyLevels()
+	dependencies := c.dependencies
+	timeout := c.shutdownTimeout
+	c.reset()
+	c.mu.Unlock()
+	var mu sync.Mutex
+	var failures []*WiringError
+	for i := len(levels) - 1; i >= 0; i-- {
+		var wg sync.WaitGroup
+		for _, key := range levels[i] {
+			dependency := dependencies[key]
+			if !isStoppable(dependency) {
+				continue
+			}
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				if err := stopDependency(ctx, timeout, key, dependency); err != nil {
+					mu.Lock()
+					defer mu.Unlock()
+					failures = append(failures, err)
+				}
+			}(key)
+		}
+		wg.Wait()
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Struct < failures[j].Struct
+	})
+	return &ShutdownError{Errors: failures}
+}
+
+// SetShutdownTimeout function limits the time each struct registered inside the
+// default Container is given to stop during Shutdown function. Zero, which is the
+// default, means each struct is limited only by the deadline of the Shutdown context.
+func SetShutdownTimeout(timeout time.Duration) {
+	defaultContainer.SetShutdownTimeout(timeout)
+}
+
+// SetShutdownTimeout method limits the time each struct registered inside the
+// Container is given to stop. For more information take a look at SetShutdownTimeout function.
+func (c *Container) SetShutdownTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdownTimeout = timeout
+}
+
+// isStoppable function reports whether the dependency implements Stopper or io.Closer interface.
+func isStoppable(dependency interface{}) bool {
+	switch dependency.(type) {
+	case Stopper, io.Closer:
+		return true
+	}
+	return false
+}
+
+// stopDependency function stops the dependency and waits until it's stopped or
+// the timeout elapses. The dependency, which didn't stop in time, is left running
+// in the background and reported as *WiringError caused by ErrShutdownTimeout.
+func stopDependency(ctx context.Context, timeout time.Duration, key string, dependency interface{}) *WiringError {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if ctx.Err() != nil {
+		return &WiringError{Err: ErrShutdownTimeout, Struct: key, Cause: ctx.Err()}
+	}
+	done := make(chan *WiringError, 1)
+	go func() {
+		if stopper, ok := dependency.(Stopper); ok {
+			log.Printf("Stopping %s", key)
+			if err := stopper.Stop(ctx); err != nil {
+				done <- &WiringError{Err: ErrCloseFailed, Struct: key, Cause: err}
+				return
+			}
+			done <- nil
+			return
+		}
+		done <- closeDependency(key, dependency)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		log.Printf("Stopping %s timed out", key)
+		return &WiringError{Err: ErrShutdownTimeout, Struct: key, Cause: ctx.Err()}
+	}
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stopRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *stopRecorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+type stopRepository struct {
+	recorder *stopRecorder
+}
+
+func (r *stopRepository) Close() error {
+	r.recorder.record("repository")
+	return nil
+}
+
+type stopCache struct {
+	recorder *stopRecorder
+	err      error
+}
+
+func (c *stopCache) Stop(context.Context) error {
+	c.recorder.record("cache")
+	return c.err
+}
+
+// Close method shouldn't be invoked as Stop method takes precedence.
+func (c *stopCache) Close() error {
+	c.recorder.record("cache closed")
+	return nil
+}
+
+type stopServer struct {
+	recorder   *stopRecorder
+	repository *stopRepository `autowire:""`
+	cache      *stopCache      `autowire:""`
+	delay      time.Duration
+}
+
+func (s *stopServer) Stop(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		s.recorder.record("server")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestShutdownReverseDependencyOrder(t *testing.T) {
+	c := NewContainer()
+	recorder := &stopRecorder{}
+	c.Autowire(&stopServer{recorder: recorder}, &stopRepository{recorder: recorder}, &stopCache{recorder: recorder})
+	assert.Nil(t, c.Shutdown(context.Background()))
+	assert.Equal(t, 3, len(recorder.calls))
+	assert.Equal(t, "server", recorder.calls[0])
+	assert.ElementsMatch(t, []string{"repository", "cache"}, recorder.calls[1:])
+	assert.Nil(t, c.Autowired(stopServer{}))
+}
+
+func TestShutdownError(t *testing.T) {
+	c := NewContainer()
+	recorder := &stopRecorder{}
+	c.Autowire(&stopCache{recorder: recorder, err: errClose})
+	err := c.Shutdown(context.Background())
+	assert.True(t, errors.Is(err, ErrCloseFailed))
+	assert.True(t, errors.Is(err, errClose))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, packageName+"/stopCache", wiringErr.Struct)
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	c := NewContainer()
+	recorder := &stopRecorder{}
+	c.Autowire(&stopServer{recorder: recorder, delay: time.Minute}, &stopRepository{recorder: recorder},
+		&stopCache{recorder: recorder})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.Shutdown(ctx)
+	assert.True(t, errors.Is(err, ErrShutdownTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var shutdownErr *ShutdownError
+	assert.True(t, errors.As(err, &shutdownErr))
+	assert.Equal(t, 3, len(shutdownErr.Errors))
+	assert.Equal(t, packageName+"/stopServer", shutdownErr.Errors[2].Struct)
+	assert.Equal(t, 0, len(recorder.calls))
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	c := NewContainer()
+	c.SetShutdownTimeout(10 * time.Millisecond)
+	recorder := &stopRecorder{}
+	c.Autowire(&stopServer{recorder: recorder, delay: time.Minute}, &stopRepository{recorder: recorder},
+		&stopCache{recorder: recorder})
+	err := c.Shutdown(context.Background())
+	var shutdownErr *ShutdownError
+	assert.True(t, errors.As(err, &shutdownErr))
+	assert.Equal(t, 1, len(shutdownErr.Errors))
+	assert.True(t, errors.Is(shutdownErr.Errors[0], ErrShutdownTimeout))
+	assert.Equal(t, packageName+"/stopServer", shutdownErr.Errors[0].Struct)
+	assert.ElementsMatch(t, []string{"repository", "cache"}, recorder.calls)
+}
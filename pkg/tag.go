@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"strings"
+)
+
+const (
+	// namePrefix represents prefix of the autowire tag option holding the bean name.
+	namePrefix = "name="
+	// optionalOption represents autowire tag option marking the dependency as optional.
+	optionalOption = "optional"
+)
+
+// tagOptions represents parsed value of the autowire tag. The tag value is a
+// comma separated list, where the first element is the qualifier (full path of
+// the dependency or its suffix) followed by the options, e.g.:
+//  `autowire:"service/AuditService"`
+//  `autowire:"name=replicaDB"`
+//  `autowire:"service/AuditService,optional"`
+//  `autowire:",new"`
+//  `autowire:",config=server"`
+type tagOptions struct {
+	qualifier string
+	name      string
+	optional  bool
+	lazy      bool
+	fresh     bool
+	config    string
+}
+
+func parseTag(tag string) tagOptions {
+	var options tagOptions
+	for i, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == optionalOption:
+			options.optional = true
+		case option == lazyOption:
+			options.lazy = true
+		case option == newOption:
+			options.fresh = true
+		case strings.HasPrefix(option, configPrefix):
+			options.config = strings.TrimPrefix(option, configPrefix)
+		case strings.HasPrefix(option, namePrefix):
+			options.name = strings.TrimPrefix(option, namePrefix)
+		case i == 0:
+			options.qualifier = option
+		}
+	}
+	return options
+}
@@ -0,0 +1,15 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseTag(t *testing.T) {
+	assert.Equal(t, tagOptions{}, parseTag(""))
+	assert.Equal(t, tagOptions{qualifier: "service/AuditService"}, parseTag("service/AuditService"))
+	assert.Equal(t, tagOptions{name: "replicaDB"}, parseTag("name=replicaDB"))
+	assert.Equal(t, tagOptions{name: "replicaDB"}, parseTag(" name=replicaDB "))
+	assert.Equal(t, tagOptions{qualifier: "sql/DB", name: "replicaDB"}, parseTag("sql/DB,name=replicaDB"))
+}
@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ValidationError represents all the failures found by Validate function.
+// ValidationError could be checked with errors.Is and errors.As functions,
+// which are matching any of the failures.
+type ValidationError struct {
+	// Errors holds the failures sorted by struct path and field.
+	Errors []*WiringError
+}
+
+// Error returns description of all the failures, each on separate line.
+func (e *ValidationError) Error() string {
+	return wiringFailures(e.Errors).describe("wiring failures")
+}
+
+// Is reports whether any of the failures matches the target.
+func (e *ValidationError) Is(target error) bool {
+	return wiringFailures(e.Errors).is(target)
+}
+
+// As finds the first failure matching the target.
+func (e *ValidationError) As(target interface{}) bool {
+	return wiringFailures(e.Errors).as(target)
+}
+
+// Validate function walks the dependency graph of the default Container and
+// returns *ValidationError listing every struct field, which is still waiting
+// for its dependency, every constructor, whose arguments couldn't be
+// resolved, and every failed Init method of Initializer interface. Conditional
+// registrations are finalized first, take a look at Finalize function. Fields marked
+// with optional option are skipped. Validate function should be invoked once all
+// the init() functions have run, e.g. at the beginning of the main function, so
+// the application doesn't start half-wired:
+//  func main() {
+//      if err := pkg.Validate(); err != nil {
+//          log.Fatalln(err)
+//      }
+//  }
+func Validate() error {
+	return defaultContainer.Validate()
+}
+
+// Validate method walks the dependency graph of the Container. For more
+// information take a look at Validate function.
+func (c *Container) Validate() error {
+	if err := c.finalize(); err != nil {
+		return err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var unresolved []*WiringError
+	for depName, depMap := range c.requiredDependencies {
+		for structType, fields := range depMap {
+			dependency, ok := c.dependencies[structType]
+			if !ok {
+				continue
+			}
+			for _, field := range fields {
+				if !field.optional && isFieldZero(dependency, field.field) {
+					unresolved = append(unresolved, &WiringError{Err: ErrNotFound, Struct: structType,
+						Field: field.field, Tag: field.tag, Dependency: depName})
+				}
+			}
+		}
+	}
+	for path, p := range c.providers {
+		t := p.constructor.Type()
+		for i := 0; i < t.NumIn(); i++ {
+			if p.lifetime == Scoped && t.In(i) == contextType {
+				continue
+			}
+			if err := c.canResolve(t.In(i), p.lifetime == Scoped); err != nil && !c.canInherit(t.In(i)) {
+				var wiringErr *WiringError
+				errors.As(err, &wiringErr)
+				wiringErr.Struct = path
+				wiringErr.Field = fmt.Sprintf("argument %d", i)
+				unresolved = append(unresolved, wiringErr)
+			}
+		}
+	}
+	unresolved = append(unresolved, c.initErrors...)
+	if len(unresolved) == 0 {
+		return nil
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+		if unresolved[i].Struct != unresolved[j].Struct {
+			return unresolved[i].Struct < unresolved[j].Struct
+		}
+		return unresolved[i].Field < unresolved[j].Field
+	})
+	return &ValidationError{Errors: unresolved}
+}
+
+// canResolve method reports *WiringError in case the argument of the constructor
+// couldn't be resolved, without constructing any dependency. Dependencies provided
+// with Scoped lifetime are considered only when scoped is true.
+func (c *Container) canResolve(t reflect.Type, scoped bool) error {
+	if t.Kind() == reflect.Interface {
+		switch paths := c.implementations(t, scoped); len(paths) {
+		case 0:
+			return &WiringError{Err: ErrNotFound, Dependency: getFullPath(t.PkgPath(), t.String())}
+		case 1:
+			return nil
+		default:
+			return &WiringError{Err: ErrAmbiguous, Dependency: getFullPath(t.PkgPath(), t.String()), Candidates: paths}
+		}
+	}
+	path := getFullPath(t.Elem().PkgPath(), t.String())
+	if _, ok := c.dependencies[path]; ok {
+		return nil
+	}
+	if _, ok := c.providers[path]; ok {
+		return nil
+	}
+	return &WiringError{Err: ErrNotFound, Dependency: path}
+}
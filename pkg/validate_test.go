@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-autowire/autowire/pkg/internal/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.Validate())
+	c.Autowire(&requiredDependencies{}, &fake.Qus{}, &optionalDependencies{})
+	c.Provide(newNamedService)
+	err := c.Validate()
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrAmbiguous))
+	assert.Equal(t, 5, len(validationErr.Errors))
+	assert.True(t, strings.HasPrefix(err.Error(), "autowire: 5 wiring failures\n"))
+	expected := []WiringError{
+		{Struct: packageName + "/internal/fake/Qus", Field: "passer", Tag: "fake/Foo", Dependency: "fake/Foo"},
+		{Struct: packageName + "/namedService", Field: "argument 0", Dependency: packageName + "/namedConfig"},
+		{Struct: packageName + "/namedService", Field: "argument 1", Dependency: packageName + "/internal/fake/Passer"},
+		{Struct: packageName + "/requiredDependencies", Field: "MyFoo", Dependency: packageName + "/internal/fake/Foo"},
+		{Struct: packageName + "/requiredDependencies", Field: "Passer", Tag: "fake/Foo", Dependency: "fake/Foo"},
+	}
+	for i, wiringErr := range validationErr.Errors {
+		assert.Equal(t, expected[i].Struct, wiringErr.Struct)
+		assert.Equal(t, expected[i].Field, wiringErr.Field)
+		assert.Equal(t, expected[i].Tag, wiringErr.Tag)
+		assert.Equal(t, expected[i].Dependency, wiringErr.Dependency)
+		assert.Contains(t, err.Error(), wiringErr.Error())
+	}
+
+	c.Autowire(&fake.Foo{})
+	err = c.Validate()
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, 1, len(validationErr.Errors))
+	c.Autowire(&namedConfig{name: "test"})
+	assert.Nil(t, c.Validate())
+}
+
+func TestValidateAmbiguousArgument(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func(passer fake.Passer) *namedConfig { return &namedConfig{} })
+	c.Autowire(&fake.Foo{}, &Foo{})
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrAmbiguous))
+}
+
+func TestValidateDefaultContainer(t *testing.T) {
+	assert.Nil(t, Validate())
+	Autowire(&fake.Bar{})
+	assert.True(t, errors.Is(Validate(), ErrNotFound))
+	assert.Equal(t, 0, len(Close()))
+	assert.Nil(t, Validate())
+}
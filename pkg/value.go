@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-autowire/autowire/pkg/internal"
+)
+
+// ValueTag represents the tag of the struct fields filled with configuration values.
+const ValueTag = "value"
+
+// injectValue method fills the field with the value of the expression, which may
+// contain ${KEY:default} placeholders. Placeholder referring to missing key without
+// default value leaves the field waiting for it, so it's reported by Validate method.
+// The caller is expected to hold the lock.
+func (c *Container) injectValue(elem reflect.Value, i int, structType string, expression string) error {
+	field := elem.Type().Field(i)
+	raw, err := internal.ResolvePlaceholders(expression, c.lookupProperty)
+	var missing *internal.MissingKeyError
+	switch {
+	case errors.As(err, &missing):
+		c.markStructUninitialized(structType, "${"+missing.Key+"}",
+			unresolvedField{field: field.Name, tag: expression})
+		return nil
+	case err != nil:
+		return &WiringError{Err: ErrInvalidValue, Struct: structType, Field: field.Name, Tag: expression, Cause: err}
+	}
+	value, err := internal.ConvertValue(raw, field.Type)
+	if err != nil {
+		return &WiringError{Err: ErrInvalidValue, Struct: structType, Field: field.Name, Tag: expression, Cause: err}
+	}
+	internal.SetFieldValue(elem, i, value.Interface())
+	for depName, depMap := range c.requiredDependencies {
+		if strings.HasPrefix(depName, "${") {
+			delete(depMap[structType], field.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type valueConfig struct {
+	APIKey  string        `value:"${AUTOWIRE_TEST_API_KEY:default}"`
+	url     string        `value:"http://${AUTOWIRE_TEST_HOST:localhost}:${AUTOWIRE_TEST_PORT:8080}"`
+	port    int           `value:"${AUTOWIRE_TEST_PORT:8080}"`
+	debug   bool          `value:"${AUTOWIRE_TEST_DEBUG:false}"`
+	timeout time.Duration `value:"${AUTOWIRE_TEST_TIMEOUT:5s}"`
+	hosts   []string      `value:"${AUTOWIRE_TEST_HOSTS:localhost}"`
+}
+
+type requiredValueConfig struct {
+	secret string `value:"${AUTOWIRE_TEST_SECRET}"`
+}
+
+type invalidValueConfig struct {
+	port int `value:"${AUTOWIRE_TEST_PORT:http}"`
+}
+
+func TestValueInjection(t *testing.T) {
+	c := NewContainer()
+	config := &valueConfig{}
+	c.Autowire(config)
+	assert.Equal(t, "default", config.APIKey)
+	assert.Equal(t, "http://localhost:8080", config.url)
+	assert.Equal(t, 8080, config.port)
+	assert.False(t, config.debug)
+	assert.Equal(t, 5*time.Second, config.timeout)
+	assert.Equal(t, []string{"localhost"}, config.hosts)
+
+	t.Setenv("AUTOWIRE_TEST_API_KEY", "secret")
+	t.Setenv("AUTOWIRE_TEST_PORT", "9090")
+	t.Setenv("AUTOWIRE_TEST_DEBUG", "true")
+	t.Setenv("AUTOWIRE_TEST_TIMEOUT", "1m")
+	t.Setenv("AUTOWIRE_TEST_HOSTS", "first, second")
+	config = &valueConfig{}
+	NewContainer().Autowire(config)
+	assert.Equal(t, "secret", config.APIKey)
+	assert.Equal(t, "http://localhost:9090", config.url)
+	assert.Equal(t, 9090, config.port)
+	assert.True(t, config.debug)
+	assert.Equal(t, time.Minute, config.timeout)
+	assert.Equal(t, []string{"first", "second"}, config.hosts)
+}
+
+func TestValueInjectionMissing(t *testing.T) {
+	c := NewContainer()
+	c.Autowire(&requiredValueConfig{})
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "secret", wiringErr.Field)
+	assert.Equal(t, "${AUTOWIRE_TEST_SECRET}", wiringErr.Dependency)
+}
+
+func TestValueInjectionInvalid(t *testing.T) {
+	err := NewContainer().TryAutowire(&invalidValueConfig{})
+	assert.True(t, errors.Is(err, ErrInvalidValue))
+	var wiringErr *WiringError
+	assert.True(t, errors.As(err, &wiringErr))
+	assert.Equal(t, "port", wiringErr.Field)
+	assert.Equal(t, "${AUTOWIRE_TEST_PORT:http}", wiringErr.Tag)
+	assert.NotNil(t, wiringErr.Cause)
+}